@@ -1,10 +1,16 @@
 package cmd
 
 import (
+	"bufio"
 	"fmt"
+	"io"
+	"os"
 	"time"
 
 	"github.com/eduardamirelly/tasker/database"
+	"github.com/eduardamirelly/tasker/dates"
+	"github.com/eduardamirelly/tasker/models"
+	"github.com/eduardamirelly/tasker/prompt"
 	"github.com/spf13/cobra"
 )
 
@@ -13,20 +19,117 @@ var addCmd = &cobra.Command{
 	Short: "Add a new task",
 	Long: `Add a new task to your task list. 
 
+With --interactive, the title, description, priority and due date are asked
+one after another instead of being given as arguments and flags.
+
+Tasks added without --priority get the priority named by
+$TASKER_DEFAULT_PRIORITY, else the default set in the config file, e.g.
+  {"defaults": {"add": {"priority": "high"}}}
+and medium when neither is set.
+
+--done records work already finished: the task is added completed, now or at
+the time given with --completed-at. A task completed in the past is also
+recorded as created then, so it never looks completed before it was added.
+
 Examples:
   tasker add "Buy groceries"
-  tasker add "Finish project" --description "Complete the final report"`,
-	Args: cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		title := args[0]
-		description, _ := cmd.Flags().GetString("description")
+  tasker add "Finish project" --description "Complete the final report"
+  tasker add "Pay rent" --due 2024-06-01
+  tasker add "Team meeting" --due "2024-06-01 14:30"
+  tasker add "Call the bank" --due "tomorrow 5pm"
+  tasker add "Weekly report" --due "next friday"
+  tasker add "Renew passport" --due "in 3 weeks"
+  tasker add "Fix login bug" --tag work --tag urgent
+  tasker add "Fix outage" --priority high
+  tasker add "Reviewed the contract" --done
+  tasker add "Paid the electricity bill" --done --completed-at yesterday
+  tasker add --interactive`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if interactive, _ := cmd.Flags().GetBool("interactive"); interactive {
+			if len(args) > 0 {
+				return fmt.Errorf("--interactive asks for the title, it can't be given as an argument")
+			}
+			return nil
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tagValues, _ := cmd.Flags().GetStringSlice("tag")
+		tags, err := normalizeTags(tagValues)
+		if err != nil {
+			fmt.Printf("Error adding task: %v\n", err)
+			return nil
+		}
+
+		now := time.Now()
 
-		if err := addTask(title, description); err != nil {
+		priority, err := addPriority(cmd)
+		if err != nil {
 			fmt.Printf("Error adding task: %v\n", err)
-			return
+			return nil
+		}
+
+		var task newTask
+		if interactive, _ := cmd.Flags().GetBool("interactive"); interactive {
+			if task, err = askNewTask(cmd.InOrStdin(), os.Stdout, now, priority); err != nil {
+				fmt.Printf("Error adding task: %v\n", err)
+				return nil
+			}
+		} else {
+			task.title = args[0]
+			task.description, _ = cmd.Flags().GetString("description")
+			task.priority = priority
+
+			if due, _ := cmd.Flags().GetString("due"); due != "" {
+				parsed, err := dates.ParseDue(due, now)
+				if err != nil {
+					fmt.Printf("Error adding task: %v\n", err)
+					return nil
+				}
+				task.dueDate = &parsed
+			}
 		}
 
-		fmt.Printf("✓ Task added: %s\n", title)
+		if done, _ := cmd.Flags().GetBool("done"); done {
+			completedAt := now
+			if value, _ := cmd.Flags().GetString("completed-at"); value != "" {
+				if completedAt, err = dates.ParseSince(value, now); err != nil {
+					fmt.Printf("Error adding task: invalid --completed-at: %v\n", err)
+					return nil
+				}
+				if completedAt.After(now) {
+					fmt.Printf("Error adding task: --completed-at cannot be in the future\n")
+					return nil
+				}
+			}
+			task.completedAt = &completedAt
+		} else if cmd.Flags().Changed("completed-at") {
+			fmt.Printf("Error adding task: --completed-at requires --done\n")
+			return nil
+		}
+
+		if err := checkNewTask(task.title, task.description, task.dueDate, now); err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("adding task: %w", err)
+		}
+
+		createdAt := now
+		if task.completedAt != nil {
+			createdAt = *task.completedAt
+		}
+		id, err := addTask(task, tags, createdAt, now)
+		if err != nil {
+			fmt.Printf("Error adding task: %v\n", err)
+			return nil
+		}
+
+		if task.completedAt != nil {
+			logInfo(id, "Task added as done: %s", task.title)
+		} else {
+			logInfo(id, "Task added: %s", task.title)
+		}
+		notifyAdded(id)
+		return nil
 	},
 }
 
@@ -34,10 +137,136 @@ func init() {
 	rootCmd.AddCommand(addCmd)
 
 	addCmd.Flags().StringP("description", "d", "", "Task description")
+	addCmd.Flags().StringSlice("tag", nil, "Tag the task (repeatable or comma-separated)")
+	addCmd.Flags().String("due", "", `Due date: YYYY-MM-DD, "YYYY-MM-DD HH:MM", "tomorrow 5pm", "next monday", "in 3 days"...`)
+	addCmd.Flags().StringP("priority", "p", models.DefaultPriority, "Task priority: low, medium or high (default from $TASKER_DEFAULT_PRIORITY when set)")
+	addCmd.Flags().BoolP("interactive", "i", false, "Ask for the task fields one by one")
+	addCmd.Flags().Bool("done", false, "Add the task already completed, to log finished work")
+	addCmd.Flags().String("completed-at", "", `With --done, when the task was completed: "2024-06-01", "2024-06-01 14:30", "yesterday", "3h"... (default: now)`)
+	addCmd.MarkFlagsMutuallyExclusive("interactive", "description")
+	addCmd.MarkFlagsMutuallyExclusive("interactive", "priority")
+	addCmd.MarkFlagsMutuallyExclusive("interactive", "due")
 }
 
-func addTask(title, description string) error {
-	query := `INSERT INTO tasks (title, description, created_at) VALUES (?, ?, ?)`
-	_, err := database.DB.Exec(query, title, description, time.Now())
-	return err
+// defaultPriorityEnv names the environment variable holding the priority of
+// tasks added without --priority
+const defaultPriorityEnv = "TASKER_DEFAULT_PRIORITY"
+
+// addPriority returns the priority of the task being added: --priority when
+// given, otherwise $TASKER_DEFAULT_PRIORITY when set, otherwise the config
+// default for --priority or medium
+func addPriority(cmd *cobra.Command) (string, error) {
+	if value := os.Getenv(defaultPriorityEnv); value != "" && !cmd.Flags().Changed("priority") {
+		priority, err := models.ParsePriority(value)
+		if err != nil {
+			return "", fmt.Errorf("$%s: %w", defaultPriorityEnv, err)
+		}
+		return priority, nil
+	}
+
+	value, _ := cmd.Flags().GetString("priority")
+	return models.ParsePriority(value)
+}
+
+// newTask holds the fields of a task about to be added
+type newTask struct {
+	title       string
+	description string
+	priority    string
+	dueDate     *time.Time
+	// completedAt is set for tasks added already done
+	completedAt *time.Time
+}
+
+// askNewTask asks for the fields of a new task on out, reading the answers
+// from in. Invalid answers are reported and asked again; only the title is
+// required, the other fields can be skipped with an empty answer, which
+// leaves the priority at defaultPriority.
+func askNewTask(in io.Reader, out io.Writer, now time.Time, defaultPriority string) (newTask, error) {
+	reader := bufio.NewReader(in)
+	task := newTask{priority: defaultPriority}
+
+	// ask repeats question until parse accepts the answer
+	ask := func(question, defaultValue string, parse func(string) error) error {
+		for {
+			answer, err := prompt.Ask(reader, out, question, defaultValue)
+			if err != nil {
+				return fmt.Errorf("input ended before all fields were answered")
+			}
+			if err := parse(answer); err != nil {
+				fmt.Fprintf(out, "Invalid answer: %v\n", err)
+				continue
+			}
+			return nil
+		}
+	}
+
+	err := ask("Title", "", func(answer string) error {
+		if answer == "" {
+			return fmt.Errorf("title cannot be empty")
+		}
+		task.title = answer
+		return nil
+	})
+	if err != nil {
+		return task, err
+	}
+
+	err = ask("Description (optional)", "", func(answer string) error {
+		task.description = answer
+		return nil
+	})
+	if err != nil {
+		return task, err
+	}
+
+	err = ask("Priority (low, medium, high)", defaultPriority, func(answer string) error {
+		priority, err := models.ParsePriority(answer)
+		task.priority = priority
+		return err
+	})
+	if err != nil {
+		return task, err
+	}
+
+	err = ask(`Due date (optional, e.g. "tomorrow 5pm")`, "", func(answer string) error {
+		if answer == "" {
+			return nil
+		}
+		due, err := dates.ParseDue(answer, now)
+		if err != nil {
+			return err
+		}
+		task.dueDate = &due
+		return nil
+	})
+	return task, err
+}
+
+// addTask inserts a new task with its tags and returns its id. The task is
+// added completed when task.completedAt is set. updatedAt is when the task
+// was written, even when createdAt is backdated, so incremental exports and
+// --modified-since still pick it up.
+func addTask(task newTask, tags []string, createdAt, updatedAt time.Time) (int, error) {
+	tx, err := database.GetDB().Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO tasks (uuid, title, description, priority, created_at, updated_at, due_date, done, completed_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	result, err := tx.Exec(query, models.NewUUID(), task.title, task.description, task.priority, createdAt, updatedAt, task.dueDate, task.completedAt != nil, task.completedAt)
+	if err != nil {
+		return 0, database.WriteError(err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	if err := saveTags(tx, id, tags); err != nil {
+		return 0, err
+	}
+
+	return int(id), tx.Commit()
 }
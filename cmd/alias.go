@@ -0,0 +1,250 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/eduardamirelly/tasker/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var aliasCmd = &cobra.Command{
+	Use:   "alias",
+	Short: "Save command lines under a short name",
+	Long: `Save a command line, such as a list with several filters, under a short
+name. Running "tasker <name>" then runs the saved command line, followed by
+any extra arguments given.
+
+Aliases are stored in the config file. They must start with a tasker command
+(not another alias) and cannot reuse the name of a command.
+
+Examples:
+  tasker alias save myday "list --due-today --sort due --limit 5"
+  tasker myday
+  tasker myday --json
+  tasker alias list
+  tasker alias remove myday`,
+}
+
+var aliasSaveCmd = &cobra.Command{
+	Use:   "save [name] [command line]",
+	Short: "Save or replace an alias",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		name, expansion := args[0], strings.TrimSpace(args[1])
+
+		if err := validateAlias(name, expansion); err != nil {
+			fmt.Printf("Error saving alias: %v\n", err)
+			return
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			fmt.Printf("Error saving alias: %v\n", err)
+			return
+		}
+		if cfg.Aliases == nil {
+			cfg.Aliases = make(map[string]string)
+		}
+		cfg.Aliases[name] = expansion
+
+		if err := config.Save(cfg); err != nil {
+			fmt.Printf("Error saving alias: %v\n", err)
+			return
+		}
+		logInfo(0, "Alias saved: %s = %s", name, expansion)
+	},
+}
+
+var aliasListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the saved aliases",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.Load()
+		if err != nil {
+			fmt.Printf("Error listing aliases: %v\n", err)
+			return
+		}
+
+		if len(cfg.Aliases) == 0 {
+			fmt.Println("No aliases saved")
+			return
+		}
+
+		names := make([]string, 0, len(cfg.Aliases))
+		for name := range cfg.Aliases {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Printf("%-20s %s\n", name, cfg.Aliases[name])
+		}
+	},
+}
+
+var aliasRemoveCmd = &cobra.Command{
+	Use:   "remove [name]",
+	Short: "Remove a saved alias",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+
+		cfg, err := config.Load()
+		if err != nil {
+			fmt.Printf("Error removing alias: %v\n", err)
+			return
+		}
+		if _, ok := cfg.Aliases[name]; !ok {
+			fmt.Printf("%s Alias not found: %s\n", iconFailed, name)
+			return
+		}
+		delete(cfg.Aliases, name)
+
+		if err := config.Save(cfg); err != nil {
+			fmt.Printf("Error removing alias: %v\n", err)
+			return
+		}
+		logInfo(0, "Alias removed: %s", name)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(aliasCmd)
+	aliasCmd.AddCommand(aliasSaveCmd, aliasListCmd, aliasRemoveCmd)
+}
+
+var aliasNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// validateAlias rejects alias names that are malformed or shadow a command,
+// and expansions that don't start with a command. Since an expansion can't
+// start with an alias, aliases never expand recursively.
+func validateAlias(name, expansion string) error {
+	if !aliasNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid alias name %q: use lowercase letters, digits, - and _", name)
+	}
+	if isCommandName(name) {
+		return fmt.Errorf("%q is already a tasker command", name)
+	}
+
+	args, err := splitCommandLine(expansion)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("the command line cannot be empty")
+	}
+	if !isCommandName(args[0]) {
+		return fmt.Errorf("the command line must start with a tasker command, not %q", args[0])
+	}
+	return nil
+}
+
+// isCommandName reports whether name is a top-level command or one of its aliases
+func isCommandName(name string) bool {
+	// help and completion are only added by cobra when the root command runs
+	if name == "help" || name == "completion" {
+		return true
+	}
+	for _, command := range rootCmd.Commands() {
+		if command.Name() == name || command.HasAlias(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// expandAlias replaces a leading alias name in args with its saved command
+// line. Global flags such as --quiet or --db path may come before the alias
+// and are kept in front of its expansion. Arguments that start with a command,
+// or with any other flag, are returned unchanged.
+func expandAlias(args []string) ([]string, error) {
+	i := skipGlobalFlags(args)
+	if i >= len(args) || strings.HasPrefix(args[i], "-") || isCommandName(args[i]) {
+		return args, nil
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+	expansion, ok := cfg.Aliases[args[i]]
+	if !ok {
+		// Let cobra report the unknown command
+		return args, nil
+	}
+
+	expanded, err := splitCommandLine(expansion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid alias %s: %w", args[i], err)
+	}
+	result := append([]string{}, args[:i]...)
+	result = append(result, expanded...)
+	return append(result, args[i+1:]...), nil
+}
+
+// skipGlobalFlags returns the index of the first argument after the leading
+// global flags of args and their values. It stops at "--" and at any flag
+// that isn't global, leaving them for cobra.
+func skipGlobalFlags(args []string) int {
+	i := 0
+	for i < len(args) && strings.HasPrefix(args[i], "-") && args[i] != "--" {
+		name, _, hasValue := strings.Cut(args[i], "=")
+		var flag *pflag.Flag
+		if strings.HasPrefix(name, "--") {
+			flag = rootCmd.PersistentFlags().Lookup(name[2:])
+		} else if len(name) == 2 {
+			flag = rootCmd.PersistentFlags().ShorthandLookup(name[1:])
+		}
+		if flag == nil {
+			return i
+		}
+		i++
+		if !hasValue && flag.NoOptDefVal == "" {
+			// The flag takes its value from the next argument
+			i++
+		}
+	}
+	return i
+}
+
+// splitCommandLine splits a command line into arguments at whitespace. Single
+// or double quotes group words containing spaces into one argument.
+func splitCommandLine(line string) ([]string, error) {
+	var (
+		args    []string
+		current strings.Builder
+		inArg   bool
+		quote   rune
+	)
+	for _, r := range line {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			current.WriteRune(r)
+		case r == '"' || r == '\'':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote in %q", quote, line)
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
@@ -0,0 +1,232 @@
+package cmd
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/eduardamirelly/tasker/database"
+	"github.com/eduardamirelly/tasker/models"
+	"github.com/spf13/cobra"
+)
+
+var attachCmd = &cobra.Command{
+	Use:   "attach [id] [path or URL]",
+	Short: "Attach a file or URL to a task",
+	Long: `Attach a file path or URL to a task, optionally with a label. Attachments
+are listed by tasker show and removed with tasker detach.
+
+The file isn't copied or checked, only its path is recorded.
+
+Examples:
+  tasker attach 3 https://github.com/org/repo/issues/42 --label issue
+  tasker attach 3 ~/Documents/report.pdf`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		id, uri := args[0], strings.TrimSpace(args[1])
+		label, _ := cmd.Flags().GetString("label")
+		label = strings.TrimSpace(label)
+
+		if uri == "" {
+			fmt.Printf("Error attaching: the path or URL cannot be empty\n")
+			return
+		}
+
+		task, err := findTaskById(id)
+		if err != nil {
+			fmt.Printf("Error finding task: %v\n", err)
+			return
+		}
+
+		if task == nil {
+			fmt.Printf("%s Task not found: %s\n", iconFailed, id)
+			return
+		}
+
+		for _, attachment := range task.Attachments {
+			if attachment.URI == uri {
+				fmt.Printf("%s Already attached to task %d: %s\n", iconFailed, task.ID, uri)
+				return
+			}
+		}
+
+		attachment := models.Attachment{Label: label, URI: uri}
+		if err := addAttachment(task.ID, attachment); err != nil {
+			fmt.Printf("Error attaching: %v\n", err)
+			return
+		}
+
+		logInfo(task.ID, "Attached to %s: %s", task.Title, formatAttachment(attachment))
+	},
+}
+
+var detachCmd = &cobra.Command{
+	Use:   "detach [id] [path, URL or label]",
+	Short: "Remove an attachment from a task",
+	Long: `Remove an attachment from a task, given its path, URL or label.
+
+Examples:
+  tasker detach 3 issue
+  tasker detach 3 ~/Documents/report.pdf`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		id, ref := args[0], strings.TrimSpace(args[1])
+
+		if ref == "" {
+			fmt.Printf("Error detaching: the path, URL or label cannot be empty\n")
+			return
+		}
+
+		task, err := findTaskById(id)
+		if err != nil {
+			fmt.Printf("Error finding task: %v\n", err)
+			return
+		}
+
+		if task == nil {
+			fmt.Printf("%s Task not found: %s\n", iconFailed, id)
+			return
+		}
+
+		removed, err := removeAttachment(task.ID, ref)
+		if err != nil {
+			fmt.Printf("Error detaching: %v\n", err)
+			return
+		}
+		if removed == 0 {
+			fmt.Printf("%s No attachment %q on task %d\n", iconFailed, ref, task.ID)
+			return
+		}
+
+		logInfo(task.ID, "Detached from %s: %s", task.Title, ref)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(attachCmd)
+	rootCmd.AddCommand(detachCmd)
+
+	attachCmd.Flags().StringP("label", "l", "", "Short name for the attachment, usable with detach")
+}
+
+// formatAttachment renders an attachment as "label: uri", or just the uri
+// when it has no label
+func formatAttachment(attachment models.Attachment) string {
+	if attachment.Label == "" {
+		return attachment.URI
+	}
+	return attachment.Label + ": " + attachment.URI
+}
+
+// addAttachment records an attachment of a task and stamps the task as
+// updated, in one transaction
+func addAttachment(taskID int, attachment models.Attachment) error {
+	tx, err := database.GetDB().Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`INSERT INTO task_attachments (task_id, label, uri) VALUES (?, ?, ?)`,
+		taskID, attachment.Label, attachment.URI); err != nil {
+		return database.WriteError(err)
+	}
+	if err := touchTask(tx, taskID); err != nil {
+		return err
+	}
+	return database.WriteError(tx.Commit())
+}
+
+// removeAttachment deletes the attachments of a task whose uri or label is
+// ref and returns how many were removed. The task is stamped as updated when
+// any was.
+func removeAttachment(taskID int, ref string) (int64, error) {
+	tx, err := database.GetDB().Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`DELETE FROM task_attachments WHERE task_id = ? AND (uri = ? OR label = ?)`,
+		taskID, ref, ref)
+	if err != nil {
+		return 0, database.WriteError(err)
+	}
+	removed, err := result.RowsAffected()
+	if err != nil || removed == 0 {
+		return removed, err
+	}
+	if err := touchTask(tx, taskID); err != nil {
+		return 0, err
+	}
+	return removed, database.WriteError(tx.Commit())
+}
+
+// touchTask sets the updated_at of a task to now within tx, for changes that
+// don't rewrite the task row itself
+func touchTask(tx *sql.Tx, taskID int) error {
+	_, err := tx.Exec(`UPDATE tasks SET updated_at = ? WHERE id = ?`, time.Now(), taskID)
+	return database.WriteError(err)
+}
+
+// loadAttachmentsOf returns the attachments of the tasks keyed by task id, in
+// the order they were added, querying tagQueryBatch tasks at a time
+func loadAttachmentsOf(tasks []models.Task) (map[int][]models.Attachment, error) {
+	attachments := make(map[int][]models.Attachment)
+	for start := 0; start < len(tasks); start += tagQueryBatch {
+		batch := tasks[start:min(start+tagQueryBatch, len(tasks))]
+		if err := loadAttachmentsBatch(attachments, batch); err != nil {
+			return nil, err
+		}
+	}
+	return attachments, nil
+}
+
+// loadAttachmentsBatch loads the attachments of the tasks in batch into attachments
+func loadAttachmentsBatch(attachments map[int][]models.Attachment, batch []models.Task) error {
+	placeholders := make([]string, len(batch))
+	args := make([]interface{}, len(batch))
+	for i, task := range batch {
+		placeholders[i] = "?"
+		args[i] = task.ID
+	}
+
+	query := `SELECT task_id, label, uri FROM task_attachments WHERE task_id IN (` + strings.Join(placeholders, ", ") + `) ORDER BY rowid`
+	rows, err := database.GetDB().Query(query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			taskID     int
+			attachment models.Attachment
+		)
+		if err := rows.Scan(&taskID, &attachment.Label, &attachment.URI); err != nil {
+			return err
+		}
+		attachments[taskID] = append(attachments[taskID], attachment)
+	}
+	return rows.Err()
+}
+
+// loadAttachments returns the attachments of a task in the order they were added
+func loadAttachments(taskID int) ([]models.Attachment, error) {
+	rows, err := database.GetDB().Query(`SELECT label, uri FROM task_attachments WHERE task_id = ? ORDER BY rowid`, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attachments []models.Attachment
+	for rows.Next() {
+		var attachment models.Attachment
+		if err := rows.Scan(&attachment.Label, &attachment.URI); err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, attachment)
+	}
+	return attachments, rows.Err()
+}
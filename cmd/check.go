@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/eduardamirelly/tasker/hooks"
+	"github.com/spf13/cobra"
+)
+
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check for tasks needing attention and run a command for each",
+	Long: `Check for tasks matching the given filters (e.g. --overdue) and optionally
+run a command for each of them, which makes it easy to drive desktop
+notifications from cron.
+
+The --exec command is a template rendered with the task fields. It is run
+directly, not through a shell, and each word is rendered separately, so task
+values never turn into extra arguments or commands.
+
+Examples:
+  tasker check --overdue
+  tasker check --tag work --priority high
+  tasker check --overdue --exec 'notify-send "Overdue task" {{.Title}}'`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !filterFlagsChanged(cmd) {
+			fmt.Printf("Error: provide at least one filter flag, e.g. --overdue\n")
+			return
+		}
+
+		execLine, _ := cmd.Flags().GetString("exec")
+		var hook *hooks.Command
+		if execLine != "" {
+			var err error
+			hook, err = hooks.Parse(execLine, templateFuncs())
+			if err != nil {
+				fmt.Printf("Error: invalid --exec command: %v\n", err)
+				return
+			}
+		}
+
+		filter, err := filterFromFlags(cmd)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			return
+		}
+		tasks, err := listTasks(filter)
+		if err != nil {
+			fmt.Printf("Error checking tasks: %v\n", err)
+			return
+		}
+
+		if hook == nil {
+			fmt.Printf("%d task(s) match\n", len(tasks))
+			if len(tasks) > 0 {
+				printTasks(tasks)
+			}
+			return
+		}
+
+		for _, task := range tasks {
+			if err := hook.Run(hookRunner, task); err != nil {
+				fmt.Printf("Error running command for task %d: %v\n", task.ID, err)
+			}
+		}
+	},
+}
+
+// hookRunner runs the --exec command of check for each matching task
+var hookRunner hooks.Runner = hooks.ExecRunner
+
+// SetHookRunner overrides how check runs its --exec command and returns the
+// previous runner, so tests can record the commands instead of running them
+func SetHookRunner(runner hooks.Runner) hooks.Runner {
+	previous := hookRunner
+	hookRunner = runner
+	return previous
+}
+
+func init() {
+	rootCmd.AddCommand(checkCmd)
+
+	checkCmd.Flags().String("exec", "", "Command template to run for each matching task")
+	addFilterFlags(checkCmd)
+}
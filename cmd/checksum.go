@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var verifyExportCmd = &cobra.Command{
+	Use:   "verify-export [file]",
+	Short: "Check an export against its checksum file",
+	Long: `Recompute the SHA-256 hash of an export and compare it with the one
+recorded next to it in <file>.sha256 by tasker export --checksum, to detect
+corrupted or truncated backups.
+
+The command exits with a non-zero status when the file doesn't match, so it
+can guard backup scripts. The checksum file uses the sha256sum format, so
+"sha256sum -c tasks.csv.sha256" works too.
+
+Examples:
+  tasker export --checksum -o backup.csv
+  tasker verify-export backup.csv`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		path := args[0]
+
+		expected, err := readChecksum(checksumPath(path))
+		if err != nil {
+			return err
+		}
+		actual, err := fileChecksum(path)
+		if err != nil {
+			return err
+		}
+
+		if actual != expected {
+			fmt.Printf("%s %s does not match its checksum\n", iconFailed, path)
+			return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", path, expected, actual)
+		}
+		fmt.Printf("%s %s matches its checksum\n", iconSuccess, path)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(verifyExportCmd)
+}
+
+// checksumPath returns the sidecar file holding the checksum of path
+func checksumPath(path string) string {
+	return path + ".sha256"
+}
+
+// fileChecksum returns the hex encoded SHA-256 hash of a file
+func fileChecksum(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// writeChecksum records the hash of path in its sidecar file, in the
+// "<hash>  <name>" format of sha256sum
+func writeChecksum(path string) error {
+	sum, err := fileChecksum(path)
+	if err != nil {
+		return err
+	}
+	line := fmt.Sprintf("%s  %s\n", sum, filepath.Base(path))
+	if err := os.WriteFile(checksumPath(path), []byte(line), 0o666); err != nil {
+		return fmt.Errorf("failed to write checksum file: %w", err)
+	}
+	return nil
+}
+
+// readChecksum returns the hash recorded in a checksum file
+func readChecksum(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("no checksum file %s (export with --checksum to create one)", path)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return "", fmt.Errorf("invalid checksum file %s: it is empty", path)
+	}
+	sum := strings.ToLower(fields[0])
+	if _, err := hex.DecodeString(sum); err != nil || len(sum) != sha256.Size*2 {
+		return "", fmt.Errorf("invalid checksum file %s: %q is not a SHA-256 hash", path, fields[0])
+	}
+	return sum, nil
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/eduardamirelly/tasker/config"
+	"github.com/spf13/cobra"
+)
+
+// applyConfigDefaults sets the flag defaults from the config file on cmd. The
+// precedence is: flag given on the command line > config > built-in default.
+// Defaults are applied without marking flags as changed, so commands still
+// see them as defaults.
+func applyConfigDefaults(cmd *cobra.Command) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	// Commands are keyed by their path below the root, e.g. "list"
+	command := strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+
+	for name, value := range cfg.CommandDefaults(command) {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil {
+			return fmt.Errorf("config sets a default for unknown flag --%s of %s", name, command)
+		}
+		if flag.Changed {
+			continue
+		}
+		if err := flag.Value.Set(value); err != nil {
+			return fmt.Errorf("invalid config default for --%s of %s: %w", name, command, err)
+		}
+	}
+	return nil
+}
@@ -1,100 +1,650 @@
 package cmd
 
 import (
+	"bufio"
+	"database/sql"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
 	"time"
 
 	"github.com/eduardamirelly/tasker/database"
 	"github.com/eduardamirelly/tasker/models"
+	"github.com/eduardamirelly/tasker/prompt"
+	"github.com/eduardamirelly/tasker/webhook"
 	"github.com/spf13/cobra"
 )
 
+// prettyDetails selects the aligned task detail layout of done and show
+var prettyDetails bool
+
 var doneCmd = &cobra.Command{
-	Use:   "done [id]",
+	Use:   "done [id or #position]",
 	Short: "Mark a task as done",
-	Long:  `Mark a task as done in the database.`,
-	Args:  cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
+	Long: `Mark a task as done in the database.
+
+Instead of an id, the same filter flags as list can be given to complete
+every pending task they match. The matching tasks are shown and must be
+confirmed, either interactively or with --yes. When stdin is not a terminal
+(pipes, cron) nothing is completed without --yes, and done exits with an
+error. Add --quiet-if-none to print nothing at all when no pending task
+matches, which keeps cron mail quiet.
+
+With --interactive, pending tasks are listed with numbers and the ones to
+complete are picked by typing their numbers, comma-separated.
+
+With --from-file, the newline-separated ids in the given file are completed
+in a single transaction. Blank lines are ignored and invalid, unknown or
+already completed ids are reported and skipped.
+
+Instead of its id, a task can be given by its position in the output of the
+last list, e.g. #2 for the second task shown. This is refused when tasks were
+added or removed since that list.
+
+--at-position N is the strict form of #N: it also refuses when any task of
+that list was edited, completed or otherwise changed since, which may have
+reordered it, so the task completed is always the one that was shown.
+
+A long or multi-line note can be piped in with --note -, which reads it from
+stdin.
+
+Completing a task that is already done keeps its original completion time;
+use --force to stamp it with the current time again.
+
+With --chain, the task to work on next (as shown by tasker next) is printed
+after completing the task, to work through the list in one session.
+
+Examples:
+  tasker done 3
+  tasker done 3 --note "Shipped in v1.2"
+  git log -1 --format=%B | tasker done 3 --note -
+  tasker done "#2"
+  tasker done --at-position 2
+  tasker done 3 --chain
+  tasker done --title-prefix "[release]" --yes
+  tasker done --tag errands --priority low --yes
+  tasker done --overdue-days 30 --yes --quiet-if-none
+  tasker done --from-file ids.txt
+  tasker done --interactive`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		note, _ := cmd.Flags().GetString("note")
+		if note == "-" {
+			interactive, _ := cmd.Flags().GetBool("interactive")
+			yes, _ := cmd.Flags().GetBool("yes")
+			if interactive || (len(args) == 0 && filterFlagsChanged(cmd) && !yes) {
+				fmt.Printf("Error: --note - reads the note from stdin, which can't also answer prompts (use --yes with filters)\n")
+				return nil
+			}
+			var err error
+			if note, err = readNote(cmd.InOrStdin()); err != nil {
+				fmt.Printf("Error reading note: %v\n", err)
+				return nil
+			}
+		}
+		if cmd.Flags().Changed("at-position") {
+			interactive, _ := cmd.Flags().GetBool("interactive")
+			if len(args) > 0 || filterFlagsChanged(cmd) || cmd.Flags().Changed("from-file") || interactive {
+				fmt.Printf("Error: --at-position cannot be combined with a task id, filter flags, --from-file or --interactive\n")
+				return nil
+			}
+			position, _ := cmd.Flags().GetInt("at-position")
+			id, err := resolvePositionStrict(fmt.Sprintf("#%d", position))
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return nil
+			}
+			args = []string{strconv.Itoa(id)}
+		}
+
+		chain, _ := cmd.Flags().GetBool("chain")
+		if chain && len(args) == 0 {
+			fmt.Printf("Error: --chain requires a task id\n")
+			return nil
+		}
+
+		if interactive, _ := cmd.Flags().GetBool("interactive"); interactive {
+			if len(args) > 0 || filterFlagsChanged(cmd) || cmd.Flags().Changed("from-file") {
+				fmt.Printf("Error: --interactive cannot be combined with a task id, filter flags or --from-file\n")
+				return nil
+			}
+			completeTasksInteractively(cmd.InOrStdin(), note)
+			return nil
+		}
+
+		if fromFile, _ := cmd.Flags().GetString("from-file"); fromFile != "" {
+			if len(args) > 0 || filterFlagsChanged(cmd) {
+				fmt.Printf("Error: --from-file cannot be combined with a task id or filter flags\n")
+				return nil
+			}
+			completeTasksFromFile(fromFile, note)
+			return nil
+		}
+
+		if len(args) == 0 {
+			if !filterFlagsChanged(cmd) {
+				fmt.Printf("Error: provide a task id or at least one filter flag\n")
+				return nil
+			}
+			yes, _ := cmd.Flags().GetBool("yes")
+			quietIfNone, _ := cmd.Flags().GetBool("quiet-if-none")
+			filter, err := filterFromFlags(cmd)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return nil
+			}
+			if filter.Done != nil && *filter.Done {
+				fmt.Printf("Error: done only completes pending tasks, --status done matches none\n")
+				return nil
+			}
+			if err := completeFilteredTasks(cmd, filter, note, yes, quietIfNone); err != nil {
+				cmd.SilenceUsage = true
+				return err
+			}
+			return nil
+		}
+
+		if filterFlagsChanged(cmd) {
+			fmt.Printf("Error: use either a task id or filter flags, not both\n")
+			return nil
+		}
+
 		id := args[0]
+		if isPosition(id) {
+			resolved, err := resolvePosition(id)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return nil
+			}
+			id = strconv.Itoa(resolved)
+		}
 
 		task, err := findTaskById(id)
 
 		if err != nil {
 			fmt.Printf("Error finding task: %v\n", err)
-			return
+			return nil
 		}
 
-		if task.ID == 0 {
-			fmt.Printf("❌ Task not found: %s\n", id)
-			return
+		if task == nil {
+			fmt.Printf("%s Task not found: %s\n", iconFailed, id)
+			return nil
 		}
 
-		if task.Done {
-			fmt.Printf("✅ Task already done!\n")
-			printTask(task)
-			return
+		force, _ := cmd.Flags().GetBool("force")
+		if !markTaskAsDone(task, note, force) || !chain {
+			return nil
 		}
 
-		markTaskAsDone(task)
+		next, err := nextTask()
+		if err != nil {
+			fmt.Printf("Error finding the next task: %v\n", err)
+			return nil
+		}
+		fmt.Println()
+		printNextTask(next)
+		return nil
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(doneCmd)
+
+	doneCmd.Flags().StringP("note", "n", "", `Note recording how or why the task was completed ("-" reads it from stdin)`)
+	doneCmd.Flags().BoolP("yes", "y", false, "Complete all tasks matching the filters without asking")
+	doneCmd.Flags().Bool("quiet-if-none", false, "Print nothing when no pending task matches the filters")
+	doneCmd.Flags().Bool("chain", false, "Show the next task to work on after completing this one")
+	doneCmd.Flags().Bool("force", false, "Re-stamp the completion time of an already completed task")
+	doneCmd.Flags().BoolVar(&prettyDetails, "pretty", false, "Print task details as an aligned block with every field")
+	doneCmd.Flags().BoolP("interactive", "i", false, "Pick the pending tasks to complete from a numbered menu")
+	doneCmd.Flags().Int("at-position", 0, "Complete the task at this position of the last list, refusing if the list changed since")
+	doneCmd.Flags().String("from-file", "", "Complete the newline-separated task ids listed in a file")
+	addFilterFlags(doneCmd)
 }
 
-func findTaskById(id string) (*models.Task, error) {
-	query := `SELECT id, title, description, done, created_at, completed_at FROM tasks WHERE id = ?`
-	rows, err := database.DB.Query(query, id)
+// completeFilteredTasks marks every pending task matching filter as done.
+// Without confirmation the matching tasks are shown and the answer is read
+// from the input of cmd; when that input is not a terminal nothing is
+// completed and an error is returned. With quietIfNone, nothing is printed
+// when no task matches.
+func completeFilteredTasks(cmd *cobra.Command, filter taskFilter, note string, confirmed, quietIfNone bool) error {
+	pending := false
+	filter.Done = &pending
+
+	tasks, err := listTasks(filter)
 	if err != nil {
-		return nil, err
+		fmt.Printf("Error finding tasks: %v\n", err)
+		return nil
+	}
+
+	if len(tasks) == 0 {
+		if !quietIfNone {
+			fmt.Println("No pending tasks match the filters")
+		}
+		return nil
 	}
-	defer rows.Close()
 
-	var task models.Task
-	for rows.Next() {
-		err := rows.Scan(&task.ID, &task.Title, &task.Description, &task.Done, &task.CreatedAt, &task.CompletedAt)
+	if !confirmed {
+		fmt.Printf("%d pending task(s) match the filters:\n", len(tasks))
+		printTasks(tasks)
+
+		in := cmd.InOrStdin()
+		ok, err := prompt.Confirm(in, cmd.OutOrStdout(), "Mark them as done?", inputIsTerminal(in))
 		if err != nil {
-			return nil, err
+			return fmt.Errorf("no tasks marked as done: %w", err)
+		}
+		if !ok {
+			fmt.Println("Aborted, no tasks marked as done (pass --yes to skip the prompt)")
+			return nil
 		}
 	}
+
+	if err := markTasksAsDone(tasks, note); err != nil {
+		fmt.Printf("Error marking tasks as done: %v\n", err)
+		return nil
+	}
+
+	for _, task := range tasks {
+		logInfo(task.ID, "Task marked as done: %s", task.Title)
+	}
+	fmt.Printf("%d task(s) marked as done\n", len(tasks))
+	return nil
+}
+
+// completeTasksInteractively lists the pending tasks with numbers and
+// completes the ones selected on in
+func completeTasksInteractively(in io.Reader, note string) {
+	pending := false
+	tasks, err := listTasks(taskFilter{Done: &pending})
+	if err != nil {
+		fmt.Printf("Error finding tasks: %v\n", err)
+		return
+	}
+
+	if len(tasks) == 0 {
+		fmt.Println("No pending tasks")
+		return
+	}
+
+	for i, task := range tasks {
+		fmt.Printf("%3d) %s (id %d)\n", i+1, task.Title, task.ID)
+	}
+	fmt.Print("Select tasks to complete (e.g. 1,3): ")
+
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	fmt.Println()
+	if err != nil && answer == "" {
+		fmt.Println("No tasks selected")
+		return
+	}
+
+	choices, err := parseSelection(answer, len(tasks))
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return
+	}
+	if len(choices) == 0 {
+		fmt.Println("No tasks selected")
+		return
+	}
+
+	selected := make([]models.Task, len(choices))
+	for i, choice := range choices {
+		selected[i] = tasks[choice-1]
+	}
+
+	if err := markTasksAsDone(selected, note); err != nil {
+		fmt.Printf("Error marking tasks as done: %v\n", err)
+		return
+	}
+
+	for _, task := range selected {
+		logInfo(task.ID, "Task marked as done: %s", task.Title)
+	}
+	fmt.Printf("%d task(s) marked as done\n", len(selected))
+}
+
+// parseSelection parses comma-separated menu numbers between 1 and max,
+// dropping repeats while keeping the order they were given in
+func parseSelection(input string, max int) ([]int, error) {
+	var choices []int
+	seen := make(map[int]bool)
+	for _, field := range strings.Split(input, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		choice, err := strconv.Atoi(field)
+		if err != nil || choice < 1 || choice > max {
+			return nil, fmt.Errorf("invalid selection %q, expected numbers from 1 to %d", field, max)
+		}
+		if !seen[choice] {
+			seen[choice] = true
+			choices = append(choices, choice)
+		}
+	}
+	return choices, nil
+}
+
+// completeTasksFromFile completes the tasks whose ids are listed one per line
+// in path, reporting the outcome for every id
+func completeTasksFromFile(path, note string) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Printf("Error reading ids: %v\n", err)
+		return
+	}
+
+	var lines []string
+	var ids []int
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		lines = append(lines, line)
+		if parsed, err := parseIDs([]string{line}); err == nil {
+			ids = append(ids, parsed[0])
+		}
+	}
+
+	if len(lines) == 0 {
+		fmt.Println("No task ids found in file")
+		return
+	}
+
+	tasks, err := listTasks(taskFilter{IDs: ids})
+	if err != nil {
+		fmt.Printf("Error finding tasks: %v\n", err)
+		return
+	}
+	byID := make(map[int]models.Task, len(tasks))
+	for _, task := range tasks {
+		byID[task.ID] = task
+	}
+
+	var pending []models.Task
+	seen := make(map[int]bool)
+	for _, line := range lines {
+		parsed, err := parseIDs([]string{line})
+		if err != nil {
+			if err := warn("invalid task id, skipping: %s", line); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+			continue
+		}
+		id := parsed[0]
+		task, ok := byID[id]
+		switch {
+		case !ok:
+			fmt.Printf("%s Task not found: %d\n", iconFailed, id)
+		case seen[id]:
+			if err := warn("duplicate task id, skipping: %d", id); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+		case task.Done:
+			fmt.Printf("%s Task already done: %d - %s\n", iconDone, id, task.Title)
+		default:
+			pending = append(pending, task)
+		}
+		seen[id] = true
+	}
+
+	if len(pending) == 0 {
+		fmt.Println("No tasks marked as done")
+		return
+	}
+
+	if err := markTasksAsDone(pending, note); err != nil {
+		fmt.Printf("Error marking tasks as done: %v\n", err)
+		return
+	}
+
+	for _, task := range pending {
+		logInfo(task.ID, "Task marked as done: %d - %s", task.ID, task.Title)
+	}
+	fmt.Printf("%d task(s) marked as done\n", len(pending))
+}
+
+// readNote reads a completion note from r, dropping the trailing newlines
+func readNote(r io.Reader) (string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// markTasksAsDone completes all given tasks within a single transaction
+func markTasksAsDone(tasks []models.Task, note string) error {
+	var completionNote *string
+	if note != "" {
+		completionNote = &note
+	}
+
+	tx, err := database.GetDB().Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	completedTime := time.Now()
+	query := `UPDATE tasks SET done = TRUE, completed_at = ?, completion_note = ?, updated_at = ? WHERE id = ?`
+	for _, task := range tasks {
+		if _, err := tx.Exec(query, completedTime, completionNote, completedTime, task.ID); err != nil {
+			return database.WriteError(err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	for _, task := range tasks {
+		task.Done = true
+		task.CompletedAt = &completedTime
+		task.CompletionNote = completionNote
+		task.UpdatedAt = &completedTime
+		notify(webhook.TaskCompleted, task, nil)
+	}
+	return nil
+}
+
+// findTaskById loads a task by its numeric id or its uuid. It returns nil,
+// without an error, when no task matches.
+func findTaskById(id string) (*models.Task, error) {
+	query := `SELECT ` + taskColumns + ` FROM tasks WHERE id = ? OR uuid = ?`
+	task, err := scanTask(database.GetDB().QueryRow(query, id, strings.ToLower(strings.TrimSpace(id))))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := []models.Task{task}
+	if err := attachTags(tasks); err != nil {
+		return nil, err
+	}
+	task = tasks[0]
+	if task.Attachments, err = loadAttachments(task.ID); err != nil {
+		return nil, err
+	}
 	return &task, nil
 }
 
-func markTaskAsDone(task *models.Task) {
+// markTaskAsDone completes a task. Already completed tasks keep their original
+// completion time unless force is set, in which case they are re-stamped (and
+// keep their existing note when no new one is given). It reports whether the
+// task is done afterwards.
+func markTaskAsDone(task *models.Task, note string, force bool) bool {
 	if task == nil {
-		fmt.Printf("❌ Task not found!\n")
-		return
+		fmt.Printf("%s Task not found!\n", iconFailed)
+		return false
+	}
+
+	if task.Done && !force {
+		fmt.Printf("%s Task already done!\n", iconDone)
+		printTask(task)
+		return true
+	}
+
+	// Store NULL rather than an empty string when no note is given
+	var completionNote *string
+	if note != "" {
+		completionNote = &note
+	} else if task.Done {
+		completionNote = task.CompletionNote
 	}
 
 	completedTime := time.Now()
-	query := `UPDATE tasks SET done = TRUE, completed_at = ? WHERE id = ?`
-	_, err := database.DB.Exec(query, completedTime, task.ID)
+	query := `UPDATE tasks SET done = TRUE, completed_at = ?, completion_note = ?, updated_at = ? WHERE id = ?`
+	if !force {
+		// Never overwrite the completion time of a task completed concurrently
+		query += ` AND done = FALSE`
+	}
+	result, err := database.GetDB().Exec(query, completedTime, completionNote, completedTime, task.ID)
 	if err != nil {
-		fmt.Printf("Error marking task as done: %v\n", err)
-		return
+		fmt.Printf("Error marking task as done: %v\n", database.WriteError(err))
+		return false
+	}
+	if !force {
+		if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+			return reportCompletedConcurrently(task.ID)
+		}
 	}
 
 	// Update the in-memory task object
 	task.Done = true
 	task.CompletedAt = &completedTime
+	task.CompletionNote = completionNote
+	task.UpdatedAt = &completedTime
+
+	logInfo(task.ID, "Task marked as done: %s", task.Title)
+	notify(webhook.TaskCompleted, *task, nil)
+	printTask(task)
+	return true
+}
 
-	fmt.Printf("✓ Task marked as done: %s\n", task.Title)
+// reportCompletedConcurrently reports a task found pending that was completed
+// (or deleted) before it could be marked as done, showing its stored
+// completion rather than a new one
+func reportCompletedConcurrently(id int) bool {
+	task, err := findTaskById(strconv.Itoa(id))
+	if err != nil {
+		fmt.Printf("Error finding task: %v\n", err)
+		return false
+	}
+	if task == nil {
+		fmt.Printf("%s Task not found!\n", iconFailed)
+		return false
+	}
+	fmt.Printf("%s Task already done!\n", iconDone)
 	printTask(task)
+	return true
 }
 
+// printTask prints the details of a task, as an aligned block listing every
+// field when --pretty is given
 func printTask(task *models.Task) {
+	if prettyDetails {
+		printTaskPretty(os.Stdout, task)
+		return
+	}
+
 	if task.Description == "" {
 		task.Description = "N/A"
 	}
-	completedAt := "N/A"
-	if task.CompletedAt != nil {
-		completedAt = task.CompletedAt.Format("2006-01-02 15:04:05")
-	}
 	fmt.Println("--------------------------------")
 	fmt.Printf("Title: %s\n", task.Title)
+	if task.UUID != "" {
+		fmt.Printf("UUID: %s\n", task.UUID)
+	}
 	fmt.Printf("Description: %s\n", task.Description)
 	fmt.Printf("Created At: %s\n", task.CreatedAt.Format("2006-01-02 15:04:05"))
-	fmt.Printf("Completed At: %s\n", completedAt)
+	fmt.Printf("Completed At: %s\n", formatCompletedAt(task.CompletedAt))
+	if task.Done && task.CompletedAt != nil && !task.CompletedAt.IsZero() {
+		fmt.Printf("Completed after: %s\n", formatDuration(task.CompletedAt.Sub(task.CreatedAt)))
+	}
+	if task.DueDate != nil {
+		fmt.Printf("Due: %s\n", task.DueDate.Local().Format(models.DueDisplayLayout))
+	}
+	if len(task.Tags) > 0 {
+		fmt.Printf("Tags: %s\n", strings.Join(task.Tags, ", "))
+	}
+	if len(task.Attachments) > 0 {
+		fmt.Println("Attachments:")
+		for _, attachment := range task.Attachments {
+			fmt.Printf("  - %s\n", formatAttachment(attachment))
+		}
+	}
+	if task.CompletionNote != nil {
+		fmt.Printf("Note: %s\n", *task.CompletionNote)
+	}
 	fmt.Println("--------------------------------")
 }
+
+// printTaskPretty writes every field of a task with the values aligned in one
+// column. Missing values are shown as "N/A" so the block always has the same shape.
+func printTaskPretty(w io.Writer, task *models.Task) {
+	orNA := func(value string) string {
+		if value == "" {
+			return "N/A"
+		}
+		return value
+	}
+
+	status := "pending"
+	if task.Done {
+		status = "done"
+	}
+	completedAfter := ""
+	if task.Done && task.CompletedAt != nil && !task.CompletedAt.IsZero() {
+		completedAfter = formatDuration(task.CompletedAt.Sub(task.CreatedAt))
+	}
+	due := ""
+	if task.DueDate != nil {
+		due = task.DueDate.Local().Format(models.DueDisplayLayout)
+	}
+	note := ""
+	if task.CompletionNote != nil {
+		note = *task.CompletionNote
+	}
+	updated := ""
+	if task.UpdatedAt != nil {
+		updated = task.UpdatedAt.Format("2006-01-02 15:04:05")
+	}
+
+	attachments := make([]string, len(task.Attachments))
+	for i, attachment := range task.Attachments {
+		attachments[i] = formatAttachment(attachment)
+	}
+
+	fields := []struct{ label, value string }{
+		{"ID", strconv.Itoa(task.ID)},
+		{"UUID", task.UUID},
+		{"Title", task.Title},
+		{"Description", task.Description},
+		{"Status", status},
+		{"Priority", task.Priority},
+		{"Due", due},
+		{"Tags", strings.Join(task.Tags, ", ")},
+		{"Attachments", strings.Join(attachments, ", ")},
+		{"Created At", task.CreatedAt.Format("2006-01-02 15:04:05")},
+		{"Completed At", formatCompletedAt(task.CompletedAt)},
+		{"Completed after", completedAfter},
+		{"Note", note},
+		{"Updated At", updated},
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, field := range fields {
+		fmt.Fprintf(tw, "%s:\t%s\n", field.label, orNA(field.value))
+	}
+	tw.Flush()
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/eduardamirelly/tasker/database"
+	"github.com/eduardamirelly/tasker/models"
+	"github.com/eduardamirelly/tasker/webhook"
+	"github.com/spf13/cobra"
+)
+
+var editCmd = &cobra.Command{
+	Use:   "edit [id]",
+	Short: "Edit a task",
+	Long: `Edit the title and/or description of a task. Only the fields passed as
+flags are changed, and a before/after diff of the changes is printed.
+
+Examples:
+  tasker edit 3 --title "Buy groceries and snacks"
+  tasker edit 3 --description "Milk, eggs, bread"`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		id := args[0]
+
+		task, err := findTaskById(id)
+		if err != nil {
+			fmt.Printf("Error finding task: %v\n", err)
+			return
+		}
+
+		if task == nil {
+			fmt.Printf("%s Task not found: %s\n", iconFailed, id)
+			return
+		}
+
+		updated := *task
+		if cmd.Flags().Changed("title") {
+			title, _ := cmd.Flags().GetString("title")
+			updated.Title = strings.TrimSpace(title)
+			if updated.Title == "" {
+				fmt.Printf("Error editing task: title cannot be empty\n")
+				return
+			}
+		}
+		if cmd.Flags().Changed("description") {
+			updated.Description, _ = cmd.Flags().GetString("description")
+		}
+
+		changes := diffTasks(*task, updated)
+		if len(changes) == 0 {
+			fmt.Printf("Nothing to update for task %d\n", task.ID)
+			return
+		}
+
+		if err := updateTask(updated); err != nil {
+			fmt.Printf("Error editing task: %v\n", err)
+			return
+		}
+
+		logInfo(updated.ID, "Task updated: %s", updated.Title)
+		notify(webhook.TaskUpdated, updated, changes)
+		printChanges(changes)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(editCmd)
+
+	editCmd.Flags().StringP("title", "t", "", "New task title")
+	editCmd.Flags().StringP("description", "d", "", "New task description")
+}
+
+// fieldChange describes one edited field of a task
+type fieldChange struct {
+	Field  string
+	Before string
+	After  string
+}
+
+// diffTasks lists the editable fields whose values differ between before and after
+func diffTasks(before, after models.Task) []fieldChange {
+	var changes []fieldChange
+	if before.Title != after.Title {
+		changes = append(changes, fieldChange{"Title", before.Title, after.Title})
+	}
+	if before.Description != after.Description {
+		changes = append(changes, fieldChange{"Description", before.Description, after.Description})
+	}
+	return changes
+}
+
+func printChanges(changes []fieldChange) {
+	for _, change := range changes {
+		fmt.Printf("  %s: %q %s %q\n", change.Field, change.Before, iconArrow, change.After)
+	}
+}
+
+func updateTask(task models.Task) error {
+	query := `UPDATE tasks SET title = ?, description = ?, updated_at = ? WHERE id = ?`
+	_, err := database.GetDB().Exec(query, task.Title, task.Description, time.Now(), task.ID)
+	return database.WriteError(err)
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import "os"
+
+// noEmojiEnv names the environment variable that, when set to any value,
+// replaces emoji in the output like --no-emoji
+const noEmojiEnv = "TASKER_NO_EMOJI"
+
+// noEmoji replaces the emoji tasker prints with plain text, for terminals
+// that can't render them
+var noEmoji bool
+
+// icon is an emoji printed by tasker along with the plain text replacing it
+// under --no-emoji
+type icon struct {
+	emoji string
+	text  string
+}
+
+// The icons printed in messages and task lists. Every emoji tasker prints is
+// declared here, so --no-emoji covers all commands.
+var (
+	iconDone    = icon{"✅", "[x]"}
+	iconPending = icon{"❌", "[ ]"}
+	iconFailed  = icon{"❌", "x"}
+	iconWarning = icon{"⚠️ ", "!"}
+	iconOverdue = icon{"⚠️", "!"}
+	iconNotDone = icon{"👌", "-"}
+	iconSuccess = icon{"✓", "OK"}
+	iconArrow   = icon{"→", "->"}
+)
+
+// String returns the emoji, or its text when emoji are turned off
+func (i icon) String() string {
+	if emojiDisabled() {
+		return i.text
+	}
+	return i.emoji
+}
+
+// emojiDisabled reports whether emoji are turned off by --no-emoji or
+// $TASKER_NO_EMOJI
+func emojiDisabled() bool {
+	return noEmoji || os.Getenv(noEmojiEnv) != ""
+}
@@ -2,105 +2,638 @@ package cmd
 
 import (
 	"encoding/csv"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
-	"strconv"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
 
 	"github.com/eduardamirelly/tasker/database"
 	"github.com/eduardamirelly/tasker/models"
+	"github.com/eduardamirelly/tasker/progress"
 	"github.com/spf13/cobra"
 )
 
 var (
-	outputFile string
+	outputFile      string
+	exportTemplate  string
+	exportIDs       []int
+	exportIDRange   string
+	exportBOM       bool
+	exportCRLF      bool
+	exportFormat    string
+	exportManifest  bool
+	exportMaxRows   int
+	exportTruncate  bool
+	exportPrecise   bool
+	exportAppend    bool
+	exportSinceLast bool
+	exportSort      string
+	exportReverse   bool
+	exportChecksum  bool
+	exportSplit     bool
 )
 
 var exportCmd = &cobra.Command{
-	Use:   "export",
-	Short: "Export tasks to CSV",
-	Long:  `Export tasks to CSV file.`,
+	Use:   "export [id...]",
+	Short: "Export tasks to CSV, JSON or SQL",
+	Long: `Export tasks to a CSV file, or a JSON file with --format json.
+
+--format sql writes the table definitions followed by one INSERT statement per
+task (and per tag and attachment), so the database can be rebuilt elsewhere,
+e.g. with "sqlite3 new.db < tasks.sql".
+
+With --manifest the JSON export is wrapped in an envelope recording when and
+by which tasker version it was made, how many tasks it holds and which
+filters were applied, which helps when archiving several exports.
+
+Use --template to render each task through a Go text/template instead,
+writing one rendered line per task. Templates can use the task fields
+(.ID, .Title, .Description, .Done, .CreatedAt, .CompletedAt) and the
+date helpers "date" and "dateFormat".
+
+By default tasks are written to tasks.csv (tasks.json or tasks.sql for the
+other formats) in the current directory, or in
+the directory named by the TASKER_EXPORT_DIR environment variable when it is
+set (the directory is created if missing). An explicit --output always wins.
+
+Pass task IDs (as arguments or with --ids) or an --id-range to export only
+those tasks.
+
+Tasks are exported oldest first. --sort and --reverse order them like list
+does, which keeps the diffs of exports tracked in git small and reproducible.
+
+With --append, tasks are added to the end of an existing output file instead
+of replacing it; the CSV header is only written when the file is new or
+empty, so repeated exports accumulate into one log.
+
+Every export of all tasks records when it ran. --since-last-export then
+exports only the tasks added, edited or completed since the last recorded
+export, so regular backups can be incremental. The first one exports
+everything. Exports of selected ids, and exports cut short by --truncate, do
+not move the marker.
+
+--checksum also writes the SHA-256 hash of the output to <output>.sha256, so
+tasker verify-export can later detect a corrupted or truncated backup.
+
+--split-by-status writes completed and pending tasks to two files named after
+the output: tasks.csv becomes tasks.done.csv and tasks.pending.csv, each with
+its own header.
+
+--max-rows guards automated exports against unexpectedly large output: the
+export fails when more tasks match, or with --truncate writes only the first
+rows and warns.
+
+Examples:
+  tasker export -o tasks.csv
+  tasker export 3 -o task3.csv
+  tasker export --ids 3,5,7
+  tasker export --id-range 10-20
+  tasker export --bom -o tasks-excel.csv
+  tasker export --bom --crlf -o tasks-windows.csv
+  tasker export --precise -o backup.csv
+  tasker export --sort title -o tasks.csv
+  tasker export --format json --sort completed --reverse
+  tasker export --append -o log.csv
+  tasker export --since-last-export -o changes.csv
+  tasker export --format json --manifest -o backup.json
+  tasker export --format sql -o tasks.sql
+  tasker export --checksum -o backup.csv
+  tasker export --split-by-status -o report.csv
+  tasker export --max-rows 1000 --truncate
+  tasker export -o tasks.txt --template '{{.ID}},{{.Title}}'
+  tasker export -o tasks.md --template '- [{{if .Done}}x{{else}} {{end}}] {{.Title}} ({{dateFormat "2006-01-02" .CreatedAt}})'`,
 	Run: func(cmd *cobra.Command, args []string) {
-		err := exportTasks()
+		filter, err := exportFilter(args)
 		if err != nil {
 			fmt.Printf("Error exporting tasks: %v\n", err)
 			return
 		}
-		fmt.Printf("Tasks exported successfully to %s\n", outputFile)
+
+		if exportFormat != "csv" && exportFormat != "json" && exportFormat != "sql" {
+			fmt.Printf("Error exporting tasks: invalid format %q, expected csv, json or sql\n", exportFormat)
+			return
+		}
+		if exportManifest && exportFormat != "json" {
+			fmt.Printf("Error exporting tasks: --manifest requires --format json\n")
+			return
+		}
+		if exportFormat != "csv" && (exportTemplate != "" || exportBOM || exportCRLF) {
+			fmt.Printf("Error exporting tasks: --template, --bom and --crlf only apply to CSV exports\n")
+			return
+		}
+		if exportTemplate != "" && exportCRLF {
+			fmt.Printf("Error exporting tasks: --crlf cannot be used with --template\n")
+			return
+		}
+		if exportFormat == "json" && exportAppend {
+			fmt.Printf("Error exporting tasks: --append cannot be used with JSON, appending would make the file invalid\n")
+			return
+		}
+		if exportFormat == "sql" && exportAppend {
+			fmt.Printf("Error exporting tasks: --append cannot be used with SQL, the statements would insert duplicate ids\n")
+			return
+		}
+
+		if err := (taskSort{Key: exportSort, Reverse: exportReverse}).validate(); err != nil {
+			fmt.Printf("Error exporting tasks: %v\n", err)
+			return
+		}
+		if exportSinceLast && (len(filter.IDs) > 0 || exportIDRange != "") {
+			fmt.Printf("Error exporting tasks: --since-last-export cannot be combined with task ids or --id-range\n")
+			return
+		}
+
+		if !cmd.Flags().Changed("output") {
+			if exportFormat != "csv" {
+				outputFile = "tasks." + exportFormat
+			}
+			outputFile, err = defaultExportPath(outputFile)
+			if err != nil {
+				fmt.Printf("Error exporting tasks: %v\n", err)
+				return
+			}
+		}
+
+		err = exportTasks(filter)
+		if err != nil {
+			fmt.Printf("Error exporting tasks: %v\n", err)
+			return
+		}
+		if exportSplit {
+			logInfo(0, "Tasks exported successfully to %s and %s", statusPath(outputFile, "done"), statusPath(outputFile, "pending"))
+			return
+		}
+		logInfo(0, "Tasks exported successfully to %s", outputFile)
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(exportCmd)
-	exportCmd.Flags().StringVarP(&outputFile, "output", "o", "tasks.csv", "Output CSV file path")
+	exportCmd.Flags().StringVarP(&outputFile, "output", "o", "tasks.csv", "Output file path")
+	exportCmd.Flags().StringVar(&exportFormat, "format", "csv", "Export format: csv, json or sql")
+	exportCmd.Flags().IntVar(&exportMaxRows, "max-rows", 0, "Fail when more tasks than this would be exported (0 means unlimited)")
+	exportCmd.Flags().BoolVar(&exportTruncate, "truncate", false, "With --max-rows, export only the first rows instead of failing")
+	exportCmd.Flags().BoolVar(&exportManifest, "manifest", false, "Wrap the JSON export in an envelope with export metadata")
+	exportCmd.Flags().StringVar(&exportTemplate, "template", "", "Go text/template rendered once per task instead of CSV")
+	exportCmd.Flags().StringVar(&exportSort, "sort", "", "Sort by id, created, completed, due, priority or title, or several comma-separated keys (default: created)")
+	exportCmd.Flags().BoolVar(&exportReverse, "reverse", false, "Reverse the sort order")
+	exportCmd.Flags().IntSliceVar(&exportIDs, "ids", nil, "Comma-separated task IDs to export (e.g. 3,5,7)")
+	exportCmd.Flags().StringVar(&exportIDRange, "id-range", "", "Inclusive task ID range to export (e.g. 10-20)")
+	exportCmd.Flags().BoolVar(&exportPrecise, "precise", false, "Write CSV timestamps as RFC 3339 with nanoseconds so re-imports are lossless")
+	exportCmd.Flags().BoolVar(&exportSinceLast, "since-last-export", false, "Only export tasks added, edited or completed since the last full export")
+	exportCmd.Flags().BoolVar(&exportAppend, "append", false, "Append to the output file instead of replacing it (CSV header only for new files)")
+	exportCmd.Flags().BoolVar(&exportCRLF, "crlf", false, "End CSV lines with CRLF instead of LF (for Windows tools)")
+	exportCmd.Flags().BoolVar(&exportChecksum, "checksum", false, "Also write the SHA-256 hash of the output to <output>.sha256")
+	exportCmd.Flags().BoolVar(&exportSplit, "split-by-status", false, "Write completed and pending tasks to separate files, e.g. tasks.done.csv and tasks.pending.csv")
+	exportCmd.Flags().BoolVar(&exportBOM, "bom", false, "Prepend a UTF-8 byte order mark to the CSV (helps Excel read unicode)")
+}
+
+// utf8BOM is the UTF-8 byte order mark Excel looks for to detect the encoding
+const utf8BOM = "\xEF\xBB\xBF"
+
+// lastExportKey is the app_state key recording when all tasks were last exported
+const lastExportKey = "last_export_at"
+
+// exportDirEnv names the environment variable holding the default export directory
+const exportDirEnv = "TASKER_EXPORT_DIR"
+
+// defaultExportPath places the default output file name in the directory from
+// TASKER_EXPORT_DIR, creating it if needed. Without the variable the name is
+// returned unchanged, i.e. relative to the current directory.
+func defaultExportPath(name string) (string, error) {
+	dir := os.Getenv(exportDirEnv)
+	if dir == "" {
+		return name, nil
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create export directory %s: %w", dir, err)
+	}
+	return filepath.Join(dir, name), nil
 }
 
-func exportTasks() error {
-	// Get all tasks from database
-	tasks, err := getAllTasks()
+// checkNotDatabase refuses output paths that point at the active database,
+// so an export can never clobber it
+func checkNotDatabase(path string) error {
+	dbPath, err := database.Path()
+	if err != nil {
+		return fmt.Errorf("failed to resolve database path: %w", err)
+	}
+	if dbPath == "" {
+		return nil
+	}
+
+	output, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("failed to resolve output path: %w", err)
+	}
+	dbPath, err = filepath.Abs(dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve database path: %w", err)
+	}
+
+	same := output == dbPath
+	if !same {
+		// Also catch symlinks and other aliases of the database file
+		outputInfo, outputErr := os.Stat(output)
+		dbInfo, dbErr := os.Stat(dbPath)
+		same = outputErr == nil && dbErr == nil && os.SameFile(outputInfo, dbInfo)
+	}
+	if same {
+		return fmt.Errorf("refusing to write to %s: it is the tasker database", path)
+	}
+	return nil
+}
+
+// exportFilter builds the filter selecting which tasks to export from the
+// positional IDs and the --ids/--id-range flags
+func exportFilter(args []string) (taskFilter, error) {
+	var filter taskFilter
+
+	ids, err := parseIDs(args)
+	if err != nil {
+		return filter, err
+	}
+	for _, id := range exportIDs {
+		if id <= 0 {
+			return filter, fmt.Errorf("invalid task id %d", id)
+		}
+	}
+	filter.IDs = append(ids, exportIDs...)
+
+	if exportIDRange != "" {
+		if len(filter.IDs) > 0 {
+			return filter, fmt.Errorf("use either task ids or --id-range, not both")
+		}
+		filter.IDFrom, filter.IDTo, err = parseIDRange(exportIDRange)
+		if err != nil {
+			return filter, err
+		}
+	}
+
+	return filter, nil
+}
+
+func exportTasks(filter taskFilter) error {
+	// Validate the template before touching the database or the output file
+	var tmpl *template.Template
+	if exportTemplate != "" {
+		var err error
+		tmpl, err = parseExportTemplate(exportTemplate)
+		if err != nil {
+			return fmt.Errorf("invalid template: %w", err)
+		}
+	}
+
+	paths := []string{outputFile}
+	if exportSplit {
+		paths = []string{statusPath(outputFile, "done"), statusPath(outputFile, "pending")}
+	}
+	for _, path := range paths {
+		if err := checkNotDatabase(path); err != nil {
+			return err
+		}
+	}
+
+	// Taken before reading the tasks, so changes made while exporting are
+	// picked up by the next incremental export
+	startedAt := time.Now()
+	if exportSinceLast {
+		value, ok, err := database.GetState(lastExportKey)
+		if err != nil {
+			return fmt.Errorf("failed to read the last export time: %w", err)
+		}
+		if ok {
+			lastExport, err := time.Parse(time.RFC3339Nano, value)
+			if err != nil {
+				return fmt.Errorf("invalid last export time %q: %w", value, err)
+			}
+			filter.ModifiedFrom = &lastExport
+		}
+	}
+
+	// Get the selected tasks from database
+	tasks, err := listTasksSorted(filter, taskSort{Key: exportSort, Reverse: exportReverse})
 	if err != nil {
 		return fmt.Errorf("failed to fetch tasks: %w", err)
 	}
 
-	// Create CSV file
-	file, err := os.Create(outputFile)
+	for _, id := range missingIDs(filter.IDs, tasks) {
+		if err := warn("task not found, skipping: %d", id); err != nil {
+			return err
+		}
+	}
+
+	matched := len(tasks)
+	tasks, err = capRows(tasks)
 	if err != nil {
-		return fmt.Errorf("failed to create CSV file: %w", err)
+		return err
+	}
+
+	outputs := []exportOutput{{outputFile, tasks}}
+	if exportSplit {
+		outputs = splitByStatus(outputFile, tasks)
+	}
+	for _, output := range outputs {
+		if err := writeExport(output.path, output.tasks, filter, tmpl); err != nil {
+			return err
+		}
+		if exportChecksum {
+			if err := writeChecksum(output.path); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Only an export of every (changed) task moves the incremental marker
+	if len(filter.IDs) == 0 && filter.IDFrom == 0 && len(tasks) == matched {
+		err := database.SetState(lastExportKey, startedAt.UTC().Format(time.RFC3339Nano))
+		if database.IsReadOnly(err) {
+			// The export itself succeeded; only --since-last-export loses track
+			return warn("the export time was not recorded: %v", err)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to record the export time: %w", err)
+		}
+	}
+	return nil
+}
+
+// exportOutput is a file written by an export and the tasks it holds
+type exportOutput struct {
+	path  string
+	tasks []models.Task
+}
+
+// splitByStatus splits the tasks to export into a file of completed tasks and
+// one of pending tasks, named after path as statusPath does
+func splitByStatus(path string, tasks []models.Task) []exportOutput {
+	done := exportOutput{path: statusPath(path, "done")}
+	pending := exportOutput{path: statusPath(path, "pending")}
+	for _, task := range tasks {
+		if task.Done {
+			done.tasks = append(done.tasks, task)
+		} else {
+			pending.tasks = append(pending.tasks, task)
+		}
+	}
+	return []exportOutput{done, pending}
+}
+
+// statusPath inserts status before the extension of path, so tasks.csv
+// becomes tasks.done.csv
+func statusPath(path, status string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "." + status + ext
+}
+
+// writeExport writes the tasks to the file at path, rendered through tmpl when
+// it is set and in the selected format otherwise
+func writeExport(path string, tasks []models.Task, filter taskFilter, tmpl *template.Template) error {
+	// Create output file, or open it for appending. Only a new or empty file
+	// gets the header and byte order mark.
+	fileFlags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	newFile := true
+	if exportAppend {
+		fileFlags = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+		if info, err := os.Stat(path); err == nil && info.Size() > 0 {
+			newFile = false
+		}
+	}
+	file, err := os.OpenFile(path, fileFlags, 0o666)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
 	}
 	defer file.Close()
 
+	// Show progress on stderr for large exports so it never mixes with the output
+	showProgress := progress.ShouldShow(len(tasks), progress.IsTerminal(os.Stderr), quiet)
+	reporter := progress.New(os.Stderr, "Exporting", len(tasks), showProgress)
+	defer reporter.Done()
+
+	if tmpl != nil {
+		return writeTemplate(file, tmpl, tasks, reporter)
+	}
+	if exportFormat == "json" {
+		var manifest *exportEnvelope
+		if exportManifest {
+			manifest = &exportEnvelope{
+				ExportedAt: time.Now().UTC(),
+				Version:    version,
+				Count:      len(tasks),
+				Filters:    filter.describe(),
+			}
+		}
+		return writeJSON(file, tasks, manifest, reporter)
+	}
+	if exportFormat == "sql" {
+		return writeSQL(file, tasks, reporter)
+	}
+	if exportBOM && newFile {
+		if _, err := io.WriteString(file, utf8BOM); err != nil {
+			return fmt.Errorf("failed to write byte order mark: %w", err)
+		}
+	}
+	return writeCSV(file, tasks, newFile, reporter)
+}
+
+// capRows enforces --max-rows on the tasks to export, truncating them with a
+// warning when --truncate is set
+func capRows(tasks []models.Task) ([]models.Task, error) {
+	if exportMaxRows < 0 {
+		return nil, fmt.Errorf("--max-rows cannot be negative")
+	}
+	if exportMaxRows == 0 || len(tasks) <= exportMaxRows {
+		return tasks, nil
+	}
+	if !exportTruncate {
+		return nil, fmt.Errorf("%d tasks match, more than --max-rows %d (use --truncate to export only the first %d)", len(tasks), exportMaxRows, exportMaxRows)
+	}
+	if err := warn("exporting only the first %d of %d tasks (--max-rows)", exportMaxRows, len(tasks)); err != nil {
+		return nil, err
+	}
+	return tasks[:exportMaxRows], nil
+}
+
+// writeCSV writes the tasks as CSV, preceded by the header row when header is set
+func writeCSV(w io.Writer, tasks []models.Task, header bool, reporter *progress.Reporter) error {
+	layout := models.CSVTimeLayout
+	if exportPrecise {
+		layout = models.CSVPreciseTimeLayout
+	}
+
 	// Create CSV writer
-	writer := csv.NewWriter(file)
+	writer := csv.NewWriter(w)
+	writer.UseCRLF = exportCRLF
 	defer writer.Flush()
 
 	// Write CSV header
-	header := []string{"ID", "Title", "Description", "Done", "Created At", "Completed At"}
-	if err := writer.Write(header); err != nil {
-		return fmt.Errorf("failed to write CSV header: %w", err)
+	if header {
+		if err := writer.Write(models.CSVHeader); err != nil {
+			return fmt.Errorf("failed to write CSV header: %w", err)
+		}
 	}
 
 	// Write task data
 	for _, task := range tasks {
-		record := []string{
-			strconv.Itoa(task.ID),
-			task.Title,
-			task.Description,
-			strconv.FormatBool(task.Done),
-			task.CreatedAt.Format("2006-01-02 15:04:05"),
+		if err := writer.Write(task.ToCSVRecordWithLayout(layout)); err != nil {
+			return fmt.Errorf("failed to write task record: %w", err)
 		}
+		reporter.Increment()
+	}
 
-		// Handle completed_at (nullable field)
-		if task.CompletedAt != nil {
-			record = append(record, task.CompletedAt.Format("2006-01-02 15:04:05"))
-		} else {
-			record = append(record, "")
-		}
+	return nil
+}
 
-		if err := writer.Write(record); err != nil {
-			return fmt.Errorf("failed to write task record: %w", err)
-		}
+// exportEnvelope is the metadata written around the tasks of a JSON export
+// made with --manifest
+type exportEnvelope struct {
+	ExportedAt time.Time         `json:"exported_at"`
+	Version    string            `json:"version"`
+	Count      int               `json:"count"`
+	Filters    map[string]string `json:"filters"`
+	Tasks      []models.Task     `json:"tasks"`
+}
+
+// writeJSON writes the tasks as an indented JSON array, or inside manifest
+// when one is given
+func writeJSON(w io.Writer, tasks []models.Task, manifest *exportEnvelope, reporter *progress.Reporter) error {
+	if tasks == nil {
+		tasks = []models.Task{}
+	}
+
+	var value interface{} = tasks
+	if manifest != nil {
+		manifest.Tasks = tasks
+		value = manifest
 	}
 
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		return fmt.Errorf("failed to write JSON: %w", err)
+	}
+	for range tasks {
+		reporter.Increment()
+	}
 	return nil
 }
 
-// getAllTasks retrieves all tasks from the database
-func getAllTasks() ([]models.Task, error) {
-	query := `SELECT id, title, description, done, created_at, completed_at FROM tasks`
-	rows, err := database.DB.Query(query)
+// sqlTimeLayout is the layout the SQLite driver stores times in, so rows
+// restored from an SQL export read back like the originals
+const sqlTimeLayout = "2006-01-02 15:04:05.999999999-07:00"
+
+// writeSQL writes the table definitions followed by INSERT statements for the
+// tasks, their tags and their attachments, wrapped in a transaction
+func writeSQL(w io.Writer, tasks []models.Task, reporter *progress.Reporter) error {
+	attachments, err := loadAttachmentsOf(tasks)
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("failed to load attachments: %w", err)
 	}
-	defer rows.Close()
 
-	var tasks []models.Task
-	for rows.Next() {
-		var task models.Task
-		err := rows.Scan(&task.ID, &task.Title, &task.Description, &task.Done, &task.CreatedAt, &task.CompletedAt)
-		if err != nil {
-			return nil, err
+	var b strings.Builder
+	b.WriteString(strings.TrimSpace(database.Schema))
+	b.WriteString("\n\nBEGIN TRANSACTION;\n")
+	for _, task := range tasks {
+		note := "NULL"
+		if task.CompletionNote != nil {
+			note = sqlString(*task.CompletionNote)
+		}
+		done := 0
+		if task.Done {
+			done = 1
+		}
+		fmt.Fprintf(&b, "INSERT INTO tasks (%s) VALUES (%d, %s, %s, %s, %d, %s, %s, %s, %s, %s, %s);\n",
+			taskColumns, task.ID, sqlNullString(task.UUID), sqlString(task.Title), sqlString(task.Description), done,
+			sqlString(task.CreatedAt.Format(sqlTimeLayout)), sqlTime(task.CompletedAt), note,
+			sqlTime(task.DueDate), sqlTime(task.UpdatedAt), sqlNullString(task.Priority))
+		for _, tag := range task.Tags {
+			fmt.Fprintf(&b, "INSERT INTO task_tags (task_id, tag) VALUES (%d, %s);\n", task.ID, sqlString(tag))
+		}
+		for _, attachment := range attachments[task.ID] {
+			fmt.Fprintf(&b, "INSERT INTO task_attachments (task_id, label, uri) VALUES (%d, %s, %s);\n",
+				task.ID, sqlString(attachment.Label), sqlString(attachment.URI))
+		}
+	}
+	b.WriteString("COMMIT;\n")
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("failed to write SQL: %w", err)
+	}
+	for range tasks {
+		reporter.Increment()
+	}
+	return nil
+}
+
+// sqlString quotes s as an SQL string literal, doubling any single quotes
+func sqlString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// sqlNullString quotes s as an SQL string literal, or NULL when it is empty
+func sqlNullString(s string) string {
+	if s == "" {
+		return "NULL"
+	}
+	return sqlString(s)
+}
+
+// sqlTime formats t as an SQL literal, or NULL when it is missing
+func sqlTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return "NULL"
+	}
+	return sqlString(t.Format(sqlTimeLayout))
+}
+
+// parseExportTemplate parses a per-task export template with the date helpers available
+func parseExportTemplate(text string) (*template.Template, error) {
+	return template.New("export").Funcs(templateFuncs()).Parse(text)
+}
+
+// templateFuncs returns the helper functions available to user-supplied task templates
+func templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"date": func(value interface{}) string {
+			return formatTemplateTime("2006-01-02 15:04:05", value)
+		},
+		"dateFormat": formatTemplateTime,
+	}
+}
+
+// formatTemplateTime formats a time.Time or *time.Time with layout, rendering
+// a nil or zero time as an empty string
+func formatTemplateTime(layout string, value interface{}) string {
+	switch t := value.(type) {
+	case time.Time:
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format(layout)
+	case *time.Time:
+		if t == nil || t.IsZero() {
+			return ""
 		}
-		tasks = append(tasks, task)
+		return t.Format(layout)
+	default:
+		return ""
 	}
+}
 
-	return tasks, nil
+// writeTemplate renders every task through tmpl, one line per task
+func writeTemplate(w io.Writer, tmpl *template.Template, tasks []models.Task, reporter *progress.Reporter) error {
+	for _, task := range tasks {
+		if err := tmpl.Execute(w, task); err != nil {
+			return fmt.Errorf("failed to render task %d: %w", task.ID, err)
+		}
+		if _, err := io.WriteString(w, "\n"); err != nil {
+			return fmt.Errorf("failed to write task %d: %w", task.ID, err)
+		}
+		reporter.Increment()
+	}
+	return nil
 }
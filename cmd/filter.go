@@ -0,0 +1,362 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eduardamirelly/tasker/dates"
+	"github.com/eduardamirelly/tasker/models"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// taskFilter holds the criteria used to narrow down which tasks a command works on
+type taskFilter struct {
+	// IDs restricts the result to the listed task IDs
+	IDs []int
+	// IDFrom and IDTo bound the task IDs inclusively; zero means unbounded
+	IDFrom int
+	IDTo   int
+
+	// Done restricts the result to completed (true) or pending (false) tasks
+	Done *bool
+	// Overdue restricts the result to pending tasks past their due date
+	Overdue bool
+	// OverdueDays, with Overdue, requires tasks to be overdue by at least this many days
+	OverdueDays int
+	// Inconsistent keeps completed tasks without a completion time and
+	// pending tasks with one
+	Inconsistent bool
+
+	TitleContains string
+	TitlePrefix   string
+	// Priorities keeps tasks having any of these priorities
+	Priorities []string
+	// Tags keeps tasks carrying any of these tags, or all of them with TagsAll
+	Tags    []string
+	TagsAll bool
+
+	// Time windows are half-open: [From, To)
+	CreatedFrom   *time.Time
+	CreatedTo     *time.Time
+	CompletedFrom *time.Time
+	CompletedTo   *time.Time
+	DueFrom       *time.Time
+	DueTo         *time.Time
+	// ModifiedFrom keeps tasks added, edited or completed at or after this time
+	ModifiedFrom *time.Time
+}
+
+// filterAnnotation marks the flags registered by addFilterFlags
+const filterAnnotation = "tasker_filter"
+
+// addFilterFlags registers the task filter flags on a command
+func addFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().String("title-contains", "", "Only tasks whose title contains this text (case-insensitive)")
+	cmd.Flags().String("title-prefix", "", "Only tasks whose title starts with this text (case-insensitive)")
+	cmd.Flags().Bool("overdue", false, "Only pending tasks past their due date")
+	cmd.Flags().Uint("overdue-days", 0, "Only pending tasks overdue by at least this many days")
+	cmd.Flags().Bool("due-today", false, "Only tasks due today")
+	cmd.Flags().Bool("due-this-week", false, "Only tasks due this week (weeks start on Monday, see TASKER_WEEK_START)")
+	cmd.Flags().String("status", "all", "Only tasks with this completion status: all, done or pending")
+	cmd.Flags().StringSlice("priority", nil, "Only tasks with this priority: low, medium or high (repeatable or comma-separated)")
+	cmd.Flags().StringSlice("tag", nil, "Only tasks carrying any of these tags (repeatable or comma-separated)")
+	cmd.Flags().Bool("tag-all", false, "Only tasks carrying every tag given with --tag")
+	cmd.Flags().String("modified-since", "", `Only tasks added, edited or completed since a date ("2024-06-01", "2024-06-01 14:30") or duration ago ("24h")`)
+
+	for _, name := range []string{"title-contains", "title-prefix", "overdue", "overdue-days", "due-today", "due-this-week",
+		"status", "priority", "tag", "tag-all", "modified-since"} {
+		cmd.Flags().SetAnnotation(name, filterAnnotation, []string{"true"})
+	}
+}
+
+// parseStatus parses a completion status: "done" and "pending" select tasks
+// in that state, "all" (or nothing) selects every task and returns nil
+func parseStatus(value string) (*bool, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "all":
+		return nil, nil
+	case "done":
+		done := true
+		return &done, nil
+	case "pending":
+		done := false
+		return &done, nil
+	}
+	return nil, fmt.Errorf("invalid status %q (valid: all, done, pending)", value)
+}
+
+// filterFlagsChanged reports whether any filter flag was given on the command line
+func filterFlagsChanged(cmd *cobra.Command) bool {
+	changed := false
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if _, ok := f.Annotations[filterAnnotation]; ok && f.Changed {
+			changed = true
+		}
+	})
+	return changed
+}
+
+// filterFromFlags builds a taskFilter from the filter flags of a command
+func filterFromFlags(cmd *cobra.Command) (taskFilter, error) {
+	var filter taskFilter
+	filter.TitleContains, _ = cmd.Flags().GetString("title-contains")
+	filter.TitlePrefix, _ = cmd.Flags().GetString("title-prefix")
+	filter.Overdue, _ = cmd.Flags().GetBool("overdue")
+	if cmd.Flags().Changed("overdue-days") {
+		days, _ := cmd.Flags().GetUint("overdue-days")
+		filter.Overdue = true
+		filter.OverdueDays = int(days)
+	}
+
+	now := time.Now()
+	if dueToday, _ := cmd.Flags().GetBool("due-today"); dueToday {
+		start, end := dates.DayBounds(now)
+		filter.restrictDue(start, end)
+	}
+	if dueThisWeek, _ := cmd.Flags().GetBool("due-this-week"); dueThisWeek {
+		start, end := dates.WeekBounds(now, dates.WeekStart())
+		filter.restrictDue(start, end)
+	}
+
+	var err error
+	status, _ := cmd.Flags().GetString("status")
+	if filter.Done, err = parseStatus(status); err != nil {
+		return filter, err
+	}
+	priorities, _ := cmd.Flags().GetStringSlice("priority")
+	for _, value := range priorities {
+		priority, err := models.ParsePriority(value)
+		if err != nil {
+			return filter, err
+		}
+		filter.Priorities = append(filter.Priorities, priority)
+	}
+	tags, _ := cmd.Flags().GetStringSlice("tag")
+	filter.TagsAll, _ = cmd.Flags().GetBool("tag-all")
+	if filter.TagsAll && len(tags) == 0 {
+		return filter, fmt.Errorf("--tag-all requires --tag")
+	}
+	if filter.Tags, err = normalizeTags(tags); err != nil {
+		return filter, err
+	}
+	if since, _ := cmd.Flags().GetString("modified-since"); since != "" {
+		from, err := dates.ParseSince(since, now)
+		if err != nil {
+			return filter, err
+		}
+		filter.ModifiedFrom = &from
+	}
+	return filter, nil
+}
+
+// whereClause builds the SQL WHERE clause (including the keyword) and its
+// arguments for the filter. It returns an empty clause when nothing is filtered.
+func (f taskFilter) whereClause() (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if f.Done != nil {
+		conditions = append(conditions, "done = ?")
+		args = append(args, *f.Done)
+	}
+	if f.Overdue {
+		conditions = append(conditions, "done = FALSE AND due_date IS NOT NULL AND datetime(due_date) < datetime(?)")
+		cutoff := time.Now().AddDate(0, 0, -f.OverdueDays)
+		args = append(args, cutoff.UTC().Format("2006-01-02 15:04:05"))
+	}
+	if f.Inconsistent {
+		conditions = append(conditions, "((done = TRUE AND completed_at IS NULL) OR (done = FALSE AND completed_at IS NOT NULL))")
+	}
+	if len(f.IDs) > 0 {
+		placeholders := make([]string, len(f.IDs))
+		for i, id := range f.IDs {
+			placeholders[i] = "?"
+			args = append(args, id)
+		}
+		conditions = append(conditions, "id IN ("+strings.Join(placeholders, ", ")+")")
+	}
+	if f.IDFrom > 0 {
+		conditions = append(conditions, "id >= ?")
+		args = append(args, f.IDFrom)
+	}
+	if f.IDTo > 0 {
+		conditions = append(conditions, "id <= ?")
+		args = append(args, f.IDTo)
+	}
+
+	if f.TitleContains != "" {
+		conditions = append(conditions, `title LIKE ? ESCAPE '\'`)
+		args = append(args, "%"+escapeLike(f.TitleContains)+"%")
+	}
+	if f.TitlePrefix != "" {
+		conditions = append(conditions, `title LIKE ? ESCAPE '\'`)
+		args = append(args, escapeLike(f.TitlePrefix)+"%")
+	}
+
+	if len(f.Priorities) > 0 {
+		placeholders := make([]string, len(f.Priorities))
+		for i, priority := range f.Priorities {
+			placeholders[i] = "?"
+			args = append(args, priority)
+		}
+		conditions = append(conditions, "priority IN ("+strings.Join(placeholders, ", ")+")")
+	}
+	if len(f.Tags) > 0 {
+		placeholders := make([]string, len(f.Tags))
+		for i, tag := range f.Tags {
+			placeholders[i] = "?"
+			args = append(args, tag)
+		}
+		subquery := "SELECT task_id FROM task_tags WHERE tag IN (" + strings.Join(placeholders, ", ") + ")"
+		if f.TagsAll {
+			// Tags are unique per task, so carrying all of them means matching as many rows
+			subquery += " GROUP BY task_id HAVING COUNT(*) = ?"
+			args = append(args, len(f.Tags))
+		}
+		conditions = append(conditions, "id IN ("+subquery+")")
+	}
+
+	addTimeBound := func(column, op string, bound *time.Time) {
+		if bound == nil {
+			return
+		}
+		// datetime() normalizes stored timestamps to UTC so bounds compare correctly
+		conditions = append(conditions, "datetime("+column+") "+op+" datetime(?)")
+		args = append(args, bound.UTC().Format("2006-01-02 15:04:05"))
+	}
+	addTimeBound("created_at", ">=", f.CreatedFrom)
+	addTimeBound("created_at", "<", f.CreatedTo)
+	addTimeBound("completed_at", ">=", f.CompletedFrom)
+	addTimeBound("completed_at", "<", f.CompletedTo)
+	addTimeBound("due_date", ">=", f.DueFrom)
+	addTimeBound("due_date", "<", f.DueTo)
+	addTimeBound("COALESCE(updated_at, created_at)", ">=", f.ModifiedFrom)
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// describe returns the criteria of the filter keyed by name, for recording
+// which filters produced a result. Unset criteria are left out.
+func (f taskFilter) describe() map[string]string {
+	criteria := make(map[string]string)
+
+	if len(f.IDs) > 0 {
+		ids := make([]string, len(f.IDs))
+		for i, id := range f.IDs {
+			ids[i] = strconv.Itoa(id)
+		}
+		criteria["ids"] = strings.Join(ids, ",")
+	}
+	if f.IDFrom > 0 || f.IDTo > 0 {
+		criteria["id_range"] = fmt.Sprintf("%d-%d", f.IDFrom, f.IDTo)
+	}
+	if f.Done != nil {
+		criteria["done"] = strconv.FormatBool(*f.Done)
+	}
+	if f.Overdue {
+		criteria["overdue_days"] = strconv.Itoa(f.OverdueDays)
+	}
+	if f.Inconsistent {
+		criteria["inconsistent"] = "true"
+	}
+	if f.TitleContains != "" {
+		criteria["title_contains"] = f.TitleContains
+	}
+	if f.TitlePrefix != "" {
+		criteria["title_prefix"] = f.TitlePrefix
+	}
+	if len(f.Priorities) > 0 {
+		criteria["priorities"] = strings.Join(f.Priorities, ",")
+	}
+	if len(f.Tags) > 0 {
+		name := "tags_any"
+		if f.TagsAll {
+			name = "tags_all"
+		}
+		criteria[name] = strings.Join(f.Tags, ",")
+	}
+
+	addTime := func(name string, bound *time.Time) {
+		if bound != nil {
+			criteria[name] = bound.UTC().Format(time.RFC3339)
+		}
+	}
+	addTime("created_from", f.CreatedFrom)
+	addTime("created_to", f.CreatedTo)
+	addTime("completed_from", f.CompletedFrom)
+	addTime("completed_to", f.CompletedTo)
+	addTime("due_from", f.DueFrom)
+	addTime("due_to", f.DueTo)
+	addTime("modified_from", f.ModifiedFrom)
+
+	return criteria
+}
+
+// restrictDue narrows the due date window to [start, end), intersecting it with
+// any window already set so combined due filters all apply
+func (f *taskFilter) restrictDue(start, end time.Time) {
+	if f.DueFrom == nil || start.After(*f.DueFrom) {
+		f.DueFrom = &start
+	}
+	if f.DueTo == nil || end.Before(*f.DueTo) {
+		f.DueTo = &end
+	}
+}
+
+// parseIDs converts task ID arguments into integers
+func parseIDs(values []string) ([]int, error) {
+	ids := make([]int, 0, len(values))
+	for _, value := range values {
+		id, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("invalid task id %q", value)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// parseIDRange parses an inclusive "from-to" task ID range such as "10-20"
+func parseIDRange(value string) (int, int, error) {
+	parts := strings.SplitN(value, "-", 2)
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid id range %q, expected from-to (e.g. 10-20)", value)
+	}
+
+	bounds, err := parseIDs(parts)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid id range %q: %w", value, err)
+	}
+	if bounds[0] > bounds[1] {
+		return 0, 0, fmt.Errorf("invalid id range %q: start is greater than end", value)
+	}
+	return bounds[0], bounds[1], nil
+}
+
+// missingIDs returns the requested IDs that are absent from tasks
+func missingIDs(requested []int, tasks []models.Task) []int {
+	found := make(map[int]bool, len(tasks))
+	for _, task := range tasks {
+		found[task.ID] = true
+	}
+
+	var missing []int
+	for _, id := range requested {
+		if !found[id] {
+			missing = append(missing, id)
+		}
+	}
+	return missing
+}
+
+// escapeLike escapes the LIKE wildcards so user input is matched literally
+func escapeLike(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return replacer.Replace(s)
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+)
+
+// formatCompletedAt renders a completion time for display, treating both a nil
+// pointer and a zero time as "N/A"
+func formatCompletedAt(completedAt *time.Time) string {
+	if completedAt == nil || completedAt.IsZero() {
+		return "N/A"
+	}
+	return completedAt.Format("2006-01-02 15:04:05")
+}
+
+// formatDuration renders a duration using its largest unit of days, hours
+// or minutes plus the next smaller one when non-zero, e.g. "2d 3h" or "45m"
+func formatDuration(d time.Duration) string {
+	if d < time.Minute {
+		return "less than a minute"
+	}
+
+	units := []struct {
+		suffix string
+		size   time.Duration
+	}{
+		{"d", 24 * time.Hour},
+		{"h", time.Hour},
+		{"m", time.Minute},
+	}
+
+	for i, unit := range units {
+		if d < unit.size {
+			continue
+		}
+		result := fmt.Sprintf("%d%s", d/unit.size, unit.suffix)
+		if i+1 < len(units) {
+			next := units[i+1]
+			if n := (d % unit.size) / next.size; n > 0 {
+				result += fmt.Sprintf(" %d%s", n, next.suffix)
+			}
+		}
+		return result
+	}
+	return ""
+}
@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/eduardamirelly/tasker/database"
+	"github.com/eduardamirelly/tasker/dates"
+	"github.com/spf13/cobra"
+)
+
+var goalCmd = &cobra.Command{
+	Use:   "goal",
+	Short: "Show progress toward your completion goals",
+	Long: `Show how many tasks were completed today and this week compared to the
+daily and weekly goals set with tasker goal set.
+
+Weeks start on Monday; set TASKER_WEEK_START to change it.
+
+Examples:
+  tasker goal set 5 --per day
+  tasker goal set 20 --per week
+  tasker goal`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		now := time.Now()
+		shown := 0
+		for _, period := range goalPeriods {
+			target, err := loadGoal(period)
+			if err != nil {
+				fmt.Printf("Error reading goal: %v\n", err)
+				return
+			}
+			if target == 0 {
+				continue
+			}
+
+			progress, err := goalProgress(period, target, now)
+			if err != nil {
+				fmt.Printf("Error computing progress: %v\n", err)
+				return
+			}
+			fmt.Println(progress)
+			shown++
+		}
+
+		if shown == 0 {
+			fmt.Println("No goal set, use e.g. tasker goal set 5 --per day")
+		}
+	},
+}
+
+var goalSetCmd = &cobra.Command{
+	Use:   "set [tasks]",
+	Short: "Set the number of tasks to complete per day or week",
+	Long: `Set how many tasks you aim to complete per day or per week. A goal of 0
+removes it.
+
+Examples:
+  tasker goal set 5 --per day
+  tasker goal set 0 --per week`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		period, _ := cmd.Flags().GetString("per")
+		if period != goalDay && period != goalWeek {
+			fmt.Printf("Error setting goal: invalid --per %q, expected day or week\n", period)
+			return
+		}
+
+		target, err := strconv.Atoi(args[0])
+		if err != nil || target < 0 {
+			fmt.Printf("Error setting goal: invalid number of tasks %q\n", args[0])
+			return
+		}
+
+		if err := database.SetState(goalKey(period), strconv.Itoa(target)); err != nil {
+			fmt.Printf("Error setting goal: %v\n", err)
+			return
+		}
+
+		if target == 0 {
+			logInfo(0, "Goal per %s removed", period)
+			return
+		}
+		logInfo(0, "Goal set: %d task(s) per %s", target, period)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(goalCmd)
+	goalCmd.AddCommand(goalSetCmd)
+
+	goalSetCmd.Flags().String("per", goalDay, "Period of the goal: day or week")
+}
+
+// Goal periods
+const (
+	goalDay  = "day"
+	goalWeek = "week"
+)
+
+// goalPeriods lists the goal periods in the order they are shown
+var goalPeriods = []string{goalDay, goalWeek}
+
+// goalKey is the app_state key storing the goal of a period
+func goalKey(period string) string {
+	return "goal_per_" + period
+}
+
+// loadGoal returns the number of tasks to complete per period, or 0 when no
+// goal is set
+func loadGoal(period string) (int, error) {
+	value, ok, err := database.GetState(goalKey(period))
+	if err != nil || !ok {
+		return 0, err
+	}
+	target, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid goal per %s %q", period, value)
+	}
+	return target, nil
+}
+
+// goalProgress counts the tasks completed in the day or week containing now
+// and renders them against target, e.g. "Today: 3/5 tasks (60%)"
+func goalProgress(period string, target int, now time.Time) (string, error) {
+	label := "Today"
+	start, end := dates.DayBounds(now)
+	if period == goalWeek {
+		label = "This week"
+		start, end = dates.WeekBounds(now, dates.WeekStart())
+	}
+
+	completed, err := countTasks(taskFilter{CompletedFrom: &start, CompletedTo: &end})
+	if err != nil {
+		return "", err
+	}
+
+	progress := fmt.Sprintf("%s: %d/%d tasks (%d%%)", label, completed, target, completed*100/target)
+	if completed >= target {
+		progress += fmt.Sprintf(" %s goal reached", iconSuccess)
+	}
+	return progress, nil
+}
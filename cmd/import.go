@@ -0,0 +1,459 @@
+package cmd
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eduardamirelly/tasker/database"
+	"github.com/eduardamirelly/tasker/models"
+	"github.com/eduardamirelly/tasker/progress"
+	"github.com/eduardamirelly/tasker/webhook"
+	"github.com/spf13/cobra"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import [file]",
+	Short: "Import tasks from a CSV or JSON file",
+	Long: `Import tasks from a CSV file in the export format, keeping their ids.
+
+Files ending in .json, or starting with "[" or "{", are read as JSON instead:
+an array of task objects as written by tasker export --format json (with or
+without --manifest). Besides the CSV columns they can carry the priority, due
+date, completion note and tags of each task. A task with a completion time is
+imported as done, and one marked done without it is completed at import time.
+
+Rows whose id already exists are skipped, or replace the existing task with
+--on-conflict overwrite. Malformed rows (a bad boolean or date, a wrong
+number of columns...) are reported with their line number and make the whole
+import fail, unless --skip-bad-rows is given to import the valid rows anyway.
+Use --dry-run to see what would happen without writing anything.
+
+A leading CSV header row naming the export columns (in any letter case, as
+spreadsheets often write them) is skipped. Pass --skip-header=false to read
+the first row as data, e.g. a task whose title is "Title".
+
+Examples:
+  tasker import tasks.csv
+  tasker import tasks.csv --dry-run
+  tasker import tasks.csv --skip-bad-rows
+  tasker import tasks.csv --on-conflict overwrite
+  tasker import tasks.json`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		onConflict, _ := cmd.Flags().GetString("on-conflict")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		skipBadRows, _ := cmd.Flags().GetBool("skip-bad-rows")
+		skipHeader, _ := cmd.Flags().GetBool("skip-header")
+
+		if onConflict != conflictSkip && onConflict != conflictOverwrite {
+			fmt.Printf("Error importing tasks: invalid --on-conflict %q, expected skip or overwrite\n", onConflict)
+			return
+		}
+
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			fmt.Printf("Error importing tasks: %v\n", err)
+			return
+		}
+
+		var rows []importRow
+		if isJSONImport(args[0], data) {
+			rows, err = readJSONImportRows(data, time.Now())
+		} else {
+			rows, err = readImportRows(bytes.NewReader(data), skipHeader)
+		}
+		if err != nil {
+			fmt.Printf("Error importing tasks: %v\n", err)
+			return
+		}
+
+		plan, err := planImport(rows, onConflict)
+		if err != nil {
+			fmt.Printf("Error importing tasks: %v\n", err)
+			return
+		}
+
+		for _, row := range plan.invalid {
+			logWarning(fmt.Sprintf("line %d: %v", row.line, row.err))
+		}
+
+		if dryRun {
+			logInfo(0, "Dry run, nothing was imported. %s", plan.summary())
+			if len(plan.invalid) > 0 && !skipBadRows {
+				logWarning("the import would fail on the malformed rows unless --skip-bad-rows is given")
+			}
+			return
+		}
+
+		if len(plan.invalid) > 0 && !skipBadRows {
+			fmt.Printf("Error importing tasks: %d malformed row(s), nothing was imported (use --skip-bad-rows to import the valid rows)\n", len(plan.invalid))
+			return
+		}
+
+		if err := applyImport(plan); err != nil {
+			fmt.Printf("Error importing tasks: %v\n", err)
+			return
+		}
+		logInfo(0, "Import finished. %s", plan.summary())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+
+	importCmd.Flags().String("on-conflict", conflictSkip, "What to do with rows whose id already exists: skip or overwrite")
+	importCmd.Flags().Bool("dry-run", false, "Validate the file and report what would be imported without writing")
+	importCmd.Flags().Bool("skip-bad-rows", false, "Import the valid rows even when some rows are malformed")
+	importCmd.Flags().Bool("skip-header", true, "Skip a leading CSV header row (set to false to read the first row as data)")
+}
+
+// Merge strategies for imported rows whose id already exists
+const (
+	conflictSkip      = "skip"
+	conflictOverwrite = "overwrite"
+)
+
+// importRow is one data row of an import file
+type importRow struct {
+	line int
+	task models.Task
+	err  error
+}
+
+// importPlan sorts the rows of an import file by what will happen to them
+type importPlan struct {
+	add       []models.Task
+	overwrite []models.Task
+	skip      []models.Task
+	invalid   []importRow
+}
+
+// summary counts the rows of the plan by outcome, listing the line numbers of
+// the malformed ones
+func (p importPlan) summary() string {
+	summary := fmt.Sprintf("added: %d, overwritten: %d, skipped: %d, invalid: %d",
+		len(p.add), len(p.overwrite), len(p.skip), len(p.invalid))
+	if len(p.invalid) == 0 {
+		return summary
+	}
+
+	lines := make([]string, len(p.invalid))
+	for i, row := range p.invalid {
+		lines[i] = strconv.Itoa(row.line)
+	}
+	return summary + " (lines " + strings.Join(lines, ", ") + ")"
+}
+
+// readImportRows parses every data row of a task CSV. A leading header row
+// is skipped when skipHeader is set. Rows that fail to parse are returned
+// with their error rather than aborting the whole file.
+func readImportRows(r io.Reader, skipHeader bool) ([]importRow, error) {
+	reader := csv.NewReader(r)
+	// Column counts are validated per row by models.TaskFromCSVRecord
+	reader.FieldsPerRecord = -1
+
+	var rows []importRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line, _ := reader.FieldPos(0)
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			rows = append(rows, importRow{line: parseErr.StartLine, err: parseErr.Err})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if skipHeader && len(rows) == 0 && line == 1 && isCSVHeader(record) {
+			continue
+		}
+
+		task, err := models.TaskFromCSVRecord(record)
+		if err == nil {
+			err = task.Validate()
+		}
+		rows = append(rows, importRow{line: line, task: task, err: err})
+	}
+	return rows, nil
+}
+
+// isJSONImport reports whether an import file holds JSON rather than CSV,
+// judging by its extension or else its first character
+func isJSONImport(path string, data []byte) bool {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return true
+	}
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(data, []byte(utf8BOM)))
+	return len(trimmed) > 0 && (trimmed[0] == '[' || trimmed[0] == '{')
+}
+
+// readJSONImportRows parses a JSON array of tasks, or the tasks of a manifest
+// envelope, numbering each with the line it starts on. Tasks that fail to
+// decode or validate are returned with their error; malformed JSON fails the
+// whole file. Tasks are completed at now when marked done without a time.
+func readJSONImportRows(data []byte, now time.Time) ([]importRow, error) {
+	data = bytes.TrimPrefix(data, []byte(utf8BOM))
+	tasks := data
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		var envelope struct {
+			Tasks json.RawMessage `json:"tasks"`
+		}
+		if err := json.Unmarshal(data, &envelope); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		if envelope.Tasks == nil {
+			return nil, fmt.Errorf("invalid JSON: expected an array of tasks or an object with a \"tasks\" array")
+		}
+		tasks = envelope.Tasks
+	}
+	// Line numbers count from the start of the file, not of the tasks array
+	base := bytes.Index(data, tasks)
+	lineAt := func(offset int64) int {
+		return 1 + bytes.Count(data[:base+int(offset)], []byte("\n"))
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(tasks))
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return nil, fmt.Errorf("invalid JSON: expected an array of tasks")
+	}
+
+	var rows []importRow
+	for decoder.More() {
+		// Skip the separator so the line is the one the task starts on
+		start := decoder.InputOffset()
+		for int(start) < len(tasks) && strings.ContainsRune(" \t\r\n,", rune(tasks[start])) {
+			start++
+		}
+		line := lineAt(start)
+
+		var task models.Task
+		if err := decoder.Decode(&task); err != nil {
+			var syntaxErr *json.SyntaxError
+			if errors.As(err, &syntaxErr) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return nil, fmt.Errorf("invalid JSON on line %d: %w", line, err)
+			}
+			rows = append(rows, importRow{line: line, err: err})
+			continue
+		}
+		err := prepareJSONTask(&task, now)
+		rows = append(rows, importRow{line: line, task: task, err: err})
+	}
+	if _, err := decoder.Token(); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	return rows, nil
+}
+
+// prepareJSONTask normalizes a task read from JSON and validates it. Missing
+// creation times become now, and done is derived from the completion time.
+func prepareJSONTask(task *models.Task, now time.Time) error {
+	if task.CreatedAt.IsZero() {
+		task.CreatedAt = now
+	}
+	if task.CompletedAt != nil {
+		task.Done = true
+	} else if task.Done {
+		task.CompletedAt = &now
+	}
+	task.UUID = models.NormalizeUUID(task.UUID)
+	if err := task.Validate(); err != nil {
+		return err
+	}
+
+	if task.Priority != "" {
+		task.Priority, _ = models.ParsePriority(task.Priority)
+	}
+	tags, err := normalizeTags(task.Tags)
+	if err != nil {
+		return err
+	}
+	if tags == nil && task.Tags != nil {
+		// An empty list still says the task has no tags, unlike a missing one
+		tags = []string{}
+	}
+	task.Tags = tags
+	return nil
+}
+
+// isCSVHeader reports whether record is the export header row, with or
+// without its trailing UUID column, ignoring letter case and surrounding
+// spaces
+func isCSVHeader(record []string) bool {
+	header := models.CSVHeader
+	if len(record) == len(header)-1 {
+		header = header[:len(header)-1]
+	}
+	return slices.EqualFunc(record, header, func(field, column string) bool {
+		return strings.EqualFold(strings.TrimSpace(strings.TrimPrefix(field, utf8BOM)), column)
+	})
+}
+
+// planImport decides for each row whether it is added, overwrites an existing
+// task or is skipped under the onConflict strategy
+func planImport(rows []importRow, onConflict string) (importPlan, error) {
+	var plan importPlan
+
+	var ids []int
+	for _, row := range rows {
+		if row.err == nil && row.task.ID > 0 {
+			ids = append(ids, row.task.ID)
+		}
+	}
+
+	existing := make(map[int]bool)
+	if len(ids) > 0 {
+		tasks, err := listTasks(taskFilter{IDs: ids})
+		if err != nil {
+			return plan, fmt.Errorf("failed to look up existing tasks: %w", err)
+		}
+		for _, task := range tasks {
+			existing[task.ID] = true
+		}
+	}
+
+	for _, row := range rows {
+		switch {
+		case row.err != nil:
+			plan.invalid = append(plan.invalid, row)
+		case row.task.ID > 0 && existing[row.task.ID] && onConflict == conflictOverwrite:
+			plan.overwrite = append(plan.overwrite, row.task)
+		case row.task.ID > 0 && existing[row.task.ID]:
+			plan.skip = append(plan.skip, row.task)
+		default:
+			plan.add = append(plan.add, row.task)
+			// Later rows repeating this id conflict with it
+			if row.task.ID > 0 {
+				existing[row.task.ID] = true
+			}
+		}
+	}
+	return plan, nil
+}
+
+// importUUID returns the uuid to store for an added task: the one the file
+// gives it, when no other task has it yet, or a new one
+func importUUID(tx *sql.Tx, task models.Task) (string, error) {
+	if task.UUID == "" {
+		return models.NewUUID(), nil
+	}
+	return task.UUID, checkUUIDFree(tx, task.UUID, task.ID)
+}
+
+// checkUUIDFree fails when a task other than the one with id already has
+// uuid, including tasks added earlier in the same import
+func checkUUIDFree(tx *sql.Tx, uuid string, id int) error {
+	var other int
+	err := tx.QueryRow(`SELECT id FROM tasks WHERE uuid = ?`, uuid).Scan(&other)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && other == id && id > 0) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("uuid %s is already used by task %d", uuid, other)
+}
+
+// applyImport writes the added and overwritten tasks of plan in one transaction
+func applyImport(plan importPlan) error {
+	tx, err := database.GetDB().Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	total := len(plan.add) + len(plan.overwrite)
+	showProgress := progress.ShouldShow(total, progress.IsTerminal(os.Stderr), quiet)
+	reporter := progress.New(os.Stderr, "Importing", total, showProgress)
+	defer reporter.Done()
+
+	now := time.Now()
+	added := make([]int, 0, len(plan.add))
+	for _, task := range plan.add {
+		var id interface{}
+		if task.ID > 0 {
+			id = task.ID
+		}
+		priority := task.Priority
+		if priority == "" {
+			priority = models.DefaultPriority
+		}
+		query := `INSERT INTO tasks (id, uuid, title, description, done, created_at, completed_at, updated_at, priority, due_date, completion_note)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+		uuid, err := importUUID(tx, task)
+		if err != nil {
+			return err
+		}
+		result, err := tx.Exec(query, id, uuid, task.Title, task.Description, task.Done, task.CreatedAt, task.CompletedAt, now,
+			priority, task.DueDate, task.CompletionNote)
+		if err != nil {
+			return database.WriteError(fmt.Errorf("failed to add task %d: %w", task.ID, err))
+		}
+		taskID, err := result.LastInsertId()
+		if err != nil {
+			return err
+		}
+		if err := saveTags(tx, taskID, task.Tags); err != nil {
+			return err
+		}
+		added = append(added, int(taskID))
+		reporter.Increment()
+	}
+
+	for _, task := range plan.overwrite {
+		if task.UUID != "" {
+			if err := checkUUIDFree(tx, task.UUID, task.ID); err != nil {
+				return err
+			}
+		}
+		// Fields the file doesn't carry keep their current values
+		query := `UPDATE tasks SET title = ?, description = ?, done = ?, created_at = ?, completed_at = ?, updated_at = ?,
+			priority = COALESCE(NULLIF(?, ''), priority), due_date = COALESCE(?, due_date), completion_note = COALESCE(?, completion_note),
+			uuid = COALESCE(NULLIF(?, ''), uuid)
+			WHERE id = ?`
+		if _, err := tx.Exec(query, task.Title, task.Description, task.Done, task.CreatedAt, task.CompletedAt, now,
+			task.Priority, task.DueDate, task.CompletionNote, task.UUID, task.ID); err != nil {
+			return database.WriteError(fmt.Errorf("failed to overwrite task %d: %w", task.ID, err))
+		}
+		if task.Tags != nil {
+			// The file gives the task's tags, which replace the current ones
+			if _, err := tx.Exec(`DELETE FROM task_tags WHERE task_id = ?`, task.ID); err != nil {
+				return database.WriteError(fmt.Errorf("failed to overwrite tags of task %d: %w", task.ID, err))
+			}
+		}
+		if err := saveTags(tx, int64(task.ID), task.Tags); err != nil {
+			return err
+		}
+		reporter.Increment()
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	for _, id := range added {
+		notifyAdded(id)
+	}
+	for _, task := range plan.overwrite {
+		notifyStored(webhook.TaskUpdated, task.ID)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/eduardamirelly/tasker/database"
+	"github.com/spf13/cobra"
+)
+
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Create or upgrade the database",
+	Long: `Create the tasker database if it doesn't exist yet, bring its schema up to
+date and print where it is stored.
+
+Every command does this implicitly, but init makes the setup explicit for
+first-time use and for scripts that provision tasker before using it. It is
+safe to run any number of times.
+
+Examples:
+  tasker init`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := database.InitDB(); err != nil {
+			fmt.Printf("Error initializing database: %v\n", err)
+			return
+		}
+		if err := database.Migrate(); err != nil {
+			fmt.Printf("Error initializing database: %v\n", err)
+			return
+		}
+
+		path, err := database.Path()
+		if err != nil {
+			fmt.Printf("Error initializing database: %v\n", err)
+			return
+		}
+		if path == "" {
+			path = "(in memory)"
+		}
+		fmt.Printf("%s Database ready: %s\n", iconSuccess, path)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(initCmd)
+}
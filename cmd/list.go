@@ -1,38 +1,297 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"strings"
+	"time"
 
+	"github.com/eduardamirelly/tasker/config"
 	"github.com/eduardamirelly/tasker/database"
+	"github.com/eduardamirelly/tasker/dates"
 	"github.com/eduardamirelly/tasker/models"
+	"github.com/eduardamirelly/tasker/progress"
 	"github.com/spf13/cobra"
 )
 
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all tasks",
-	Long:  `List all tasks saved in the database.`,
+	Long: `List all tasks saved in the database.
+
+When printing to a terminal without --sort, the newest tasks are shown first
+and only the latest 20 unless --limit or --all is given. Output piped to other
+programs keeps listing every task, oldest first. Use --sort created to always
+list oldest first. --sort also takes several comma-separated keys, each one
+ordering the tasks tied on the previous ones: --sort priority,due orders tasks
+of the same priority by due date.
+
+--format csv prints the listed tasks as CSV, in the same columns as export,
+and --format json as a JSON array like --json. Both follow the filters and
+sort of the list, for quick piping without writing an export file.
+
+--completed-this-month and --month 2024-05 list the tasks completed during a
+calendar month, in local time, e.g. for a monthly retrospective.
+
+--inconsistent lists the tasks whose data disagrees with itself: tasks marked
+done without a completion time, and pending tasks with one. Such rows come
+from older versions or edits made directly in the database.
+
+The listed tasks can then be referred to by their position, e.g. tasker done
+#2 completes the second task shown. --numbered prints the positions.
+
+Examples:
+  tasker list
+  tasker list --title-contains report
+  tasker list --title-prefix "Fix"
+  tasker list --jsonl | jq .title
+  tasker list --json --indent
+  tasker list --format csv --tag work > work.csv
+  tasker list --json --omit-empty-fields
+  tasker list --title-contains bug --count
+  tasker list --done-count
+  tasker list --due-this-week --pending-count
+  tasker list --sort title --reverse
+  tasker list --sort completed --desc
+  tasker list --sort priority,due
+  tasker list --limit 5
+  tasker list --numbered
+  tasker list --ascii
+  tasker list --max-desc-width 40
+  tasker list --all
+  tasker list --sort completed --nulls first
+  tasker list --overdue-days 7
+  tasker list --completed-this-month
+  tasker list --month 2024-05
+  tasker list --modified-since 2024-06-01
+  tasker list --modified-since 24h
+  tasker list --status pending
+  tasker list --priority high --priority medium
+  tasker list --tag work --tag urgent
+  tasker list --tag work,urgent --tag-all
+  tasker list --inconsistent
+
+Default flags can be set in the config file, e.g.
+  {"defaults": {"list": {"sort": "due", "overdue": "true"}}}
+Flags given on the command line always take precedence.
+
+Tasks can be prefixed with an icon per tag, also set in the config file, e.g.
+  {"tag_icons": {"work": "💼", "home": "🏠"}}
+--ascii or the NO_COLOR environment variable turn them off.
+
+Pending tasks past their due date are flagged with ⚠️ OVERDUE.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		result, err := listTasks()
+		jsonLines, _ := cmd.Flags().GetBool("jsonl")
+		jsonArray, _ := cmd.Flags().GetBool("json")
+		indent, _ := cmd.Flags().GetBool("indent")
+		omitEmpty, _ := cmd.Flags().GetBool("omit-empty-fields")
+		countOnly, _ := cmd.Flags().GetBool("count")
+		doneCount, _ := cmd.Flags().GetBool("done-count")
+		pendingCount, _ := cmd.Flags().GetBool("pending-count")
+		format, _ := cmd.Flags().GetString("format")
+
+		if jsonLines && jsonArray {
+			fmt.Printf("Error listing tasks: use either --json or --jsonl, not both\n")
+			return
+		}
+		switch format {
+		case "text":
+		case "csv", "json":
+			if jsonLines || jsonArray {
+				fmt.Printf("Error listing tasks: use either --format or --json/--jsonl, not both\n")
+				return
+			}
+			if format == "json" {
+				jsonArray = true
+			}
+		default:
+			fmt.Printf("Error listing tasks: invalid --format %q (use text, csv or json)\n", format)
+			return
+		}
+		csvOutput := format == "csv"
+		if indent && !jsonArray {
+			fmt.Printf("Error listing tasks: --indent requires --json or --format json\n")
+			return
+		}
+		if omitEmpty && !jsonArray && !jsonLines {
+			fmt.Printf("Error listing tasks: --omit-empty-fields requires --json, --jsonl or --format json\n")
+			return
+		}
+
+		filter, err := filterFromFlags(cmd)
 		if err != nil {
 			fmt.Printf("Error listing tasks: %v\n", err)
 			return
 		}
+		filter.Inconsistent, _ = cmd.Flags().GetBool("inconsistent")
+		if completedThisMonth, _ := cmd.Flags().GetBool("completed-this-month"); completedThisMonth {
+			start, end := dates.MonthBounds(time.Now())
+			filter.CompletedFrom, filter.CompletedTo = &start, &end
+		}
+		if month, _ := cmd.Flags().GetString("month"); month != "" {
+			start, end, err := dates.ParseMonth(month, time.Local)
+			if err != nil {
+				fmt.Printf("Error listing tasks: %v\n", err)
+				return
+			}
+			filter.CompletedFrom, filter.CompletedTo = &start, &end
+		}
+
+		if doneCount || pendingCount {
+			if filter.Done != nil {
+				fmt.Printf("Error listing tasks: use either --status or --done-count/--pending-count, not both\n")
+				return
+			}
+			// Narrow the filter to the requested status and count like --count
+			done := doneCount
+			filter.Done = &done
+			countOnly = true
+		}
+		if countOnly {
+			count, err := countTasks(filter)
+			if err != nil {
+				fmt.Printf("Error counting tasks: %v\n", err)
+				return
+			}
+			fmt.Println(count)
+			return
+		}
+
+		sortBy, _ := cmd.Flags().GetString("sort")
+		reverse, _ := cmd.Flags().GetBool("reverse")
+		if desc, _ := cmd.Flags().GetBool("desc"); desc {
+			reverse = true
+		}
+		nulls, _ := cmd.Flags().GetString("nulls")
+		order := taskSort{Key: sortBy, Reverse: reverse, Nulls: nulls}
+		if order.Key == "" && cmd.Flags().Changed("overdue-days") {
+			// Most neglected tasks first
+			order.Key = "due"
+		}
+
+		limit, _ := cmd.Flags().GetInt("limit")
+		all, _ := cmd.Flags().GetBool("all")
+		if order.Key == "" && !order.Reverse && !jsonLines && !jsonArray && !csvOutput && stdoutIsTerminal() {
+			// People at a terminal mostly care about what they added recently
+			order = taskSort{Key: "created", Reverse: true}
+			if !cmd.Flags().Changed("limit") && limit == 0 {
+				limit = defaultListLimit
+			}
+		}
+		if all {
+			limit = 0
+		}
+		if limit < 0 {
+			fmt.Printf("Error listing tasks: --limit cannot be negative\n")
+			return
+		}
+		if maxDescWidth, _ := cmd.Flags().GetInt("max-desc-width"); maxDescWidth < 0 {
+			fmt.Printf("Error listing tasks: --max-desc-width cannot be negative\n")
+			return
+		}
+		if err := order.validate(); err != nil {
+			fmt.Printf("Error listing tasks: %v\n", err)
+			return
+		}
+
+		result, err := listTasksSorted(filter, order)
+		if err != nil {
+			fmt.Printf("Error listing tasks: %v\n", err)
+			return
+		}
+		total := len(result)
+		if limit > 0 && total > limit {
+			result = result[:limit]
+		}
+		if jsonLines {
+			if err := printTasksJSONLines(result, omitEmpty); err != nil {
+				fmt.Printf("Error listing tasks: %v\n", err)
+			}
+			return
+		}
+		if jsonArray {
+			if err := printTasksJSON(result, indent, omitEmpty); err != nil {
+				fmt.Printf("Error listing tasks: %v\n", err)
+			}
+			return
+		}
+		if csvOutput {
+			reporter := progress.New(os.Stderr, "Listing", len(result), false)
+			if err := writeCSV(os.Stdout, result, true, reporter); err != nil {
+				fmt.Printf("Error listing tasks: %v\n", err)
+			}
+			return
+		}
+		if err := saveListPositions(result); err != nil {
+			fmt.Printf("Error listing tasks: %v\n", err)
+			return
+		}
 		if len(result) == 0 {
 			emptyTasks()
 			return
 		}
-		printTasks(result)
+		var style listStyle
+		style.Numbered, _ = cmd.Flags().GetBool("numbered")
+		style.ASCII, _ = cmd.Flags().GetBool("ascii")
+		style.ASCII = style.ASCII || emojiDisabled()
+		style.MaxDescWidth, _ = cmd.Flags().GetInt("max-desc-width")
+		if !style.ASCII && os.Getenv("NO_COLOR") == "" {
+			cfg, err := config.Load()
+			if err != nil {
+				fmt.Printf("Error listing tasks: %v\n", err)
+				return
+			}
+			style.TagIcons = cfg.TagIcons
+		}
+		printTaskList(result, style)
+		if len(result) < total {
+			fmt.Printf("Showing %d of %d tasks (use --all to see everything)\n", len(result), total)
+		}
 	},
 }
 
+// defaultListLimit is how many tasks list shows at a terminal by default
+const defaultListLimit = 20
+
 func init() {
 	rootCmd.AddCommand(listCmd)
+
+	addFilterFlags(listCmd)
+	listCmd.Flags().Bool("jsonl", false, "Print one JSON object per task per line")
+	listCmd.Flags().Bool("json", false, "Print the tasks as a JSON array")
+	listCmd.Flags().Bool("indent", false, "Indent the --json output for reading")
+	listCmd.Flags().String("format", "text", "Output format: text, csv (with a header row, as export writes it) or json")
+	listCmd.Flags().Bool("omit-empty-fields", false, "Drop empty fields such as a blank description from JSON objects")
+	listCmd.Flags().Bool("count", false, "Print only the number of matching tasks")
+	listCmd.Flags().Bool("done-count", false, "Print only the number of matching completed tasks")
+	listCmd.Flags().Bool("pending-count", false, "Print only the number of matching pending tasks")
+	listCmd.MarkFlagsMutuallyExclusive("count", "done-count", "pending-count")
+	listCmd.Flags().String("sort", "", "Sort by id, created, completed, due, priority or title, or several comma-separated keys (default: created)")
+	listCmd.Flags().Bool("reverse", false, "Reverse the sort order")
+	listCmd.Flags().Bool("desc", false, "Sort in descending order, the same as --reverse")
+	listCmd.Flags().Bool("numbered", false, "Number the tasks, for use as #N with done")
+	listCmd.Flags().Bool("ascii", false, "Print only ASCII: no tag icons, and [x]/[ ] for the status")
+	listCmd.Flags().Int("max-desc-width", 0, "Cut descriptions longer than this many characters with an ellipsis (0 means no limit)")
+	listCmd.Flags().Int("limit", 0, "Show at most this many tasks (0 means all; 20 by default at a terminal)")
+	listCmd.Flags().Bool("all", false, "Show every matching task, overriding --limit")
+	listCmd.Flags().String("nulls", "last", "Place tasks without a completion or due date first or last when sorting by them")
+	listCmd.Flags().Bool("inconsistent", false, "Only tasks done without a completion time, or pending with one")
+	listCmd.Flags().Bool("completed-this-month", false, "Only tasks completed during the current calendar month")
+	listCmd.Flags().String("month", "", "Only tasks completed during this calendar month, written YYYY-MM")
+	listCmd.MarkFlagsMutuallyExclusive("completed-this-month", "month")
 }
 
-func listTasks() ([]models.Task, error) {
-	query := `SELECT id, title, description, done, created_at, completed_at FROM tasks`
-	rows, err := database.DB.Query(query)
+// listTasks returns the tasks matching filter in the default order
+func listTasks(filter taskFilter) ([]models.Task, error) {
+	return listTasksSorted(filter, taskSort{})
+}
+
+// listTasksSorted returns the tasks matching filter in the given order
+func listTasksSorted(filter taskFilter, order taskSort) ([]models.Task, error) {
+	where, args := filter.whereClause()
+	query := `SELECT ` + taskColumns + ` FROM tasks` + where + order.orderClause()
+	rows, err := database.GetDB().Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -40,15 +299,27 @@ func listTasks() ([]models.Task, error) {
 
 	var tasks []models.Task
 	for rows.Next() {
-		var task models.Task
-		err := rows.Scan(&task.ID, &task.Title, &task.Description, &task.Done, &task.CreatedAt, &task.CompletedAt)
+		task, err := scanTask(rows)
 		if err != nil {
 			return nil, err
 		}
 		tasks = append(tasks, task)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tasks, attachTags(tasks)
+}
+
+// countTasks returns the number of tasks matching filter without loading them
+func countTasks(filter taskFilter) (int, error) {
+	where, args := filter.whereClause()
+	query := `SELECT COUNT(*) FROM tasks` + where
 
-	return tasks, nil
+	var count int
+	err := database.GetDB().QueryRow(query, args...).Scan(&count)
+	return count, err
 }
 
 func emptyTasks() {
@@ -56,20 +327,141 @@ func emptyTasks() {
 }
 
 func printTasks(tasks []models.Task) {
-	for _, task := range tasks {
-		done := "✅"
+	printTaskList(tasks, listStyle{})
+}
+
+// listStyle controls how printTaskList decorates the tasks
+type listStyle struct {
+	// Numbered precedes each task with its position ("#1 ") in the list
+	Numbered bool
+	// ASCII shows the status as [x] or [ ] instead of an emoji
+	ASCII bool
+	// TagIcons maps a tag to the icon prefixed to the titles of its tasks
+	TagIcons map[string]string
+	// MaxDescWidth cuts longer descriptions to this many characters, when
+	// positive
+	MaxDescWidth int
+}
+
+// printTaskList prints the tasks decorated according to style
+func printTaskList(tasks []models.Task, style listStyle) {
+	now := time.Now()
+	overdue := " " + iconOverdue.emoji + " OVERDUE"
+	if style.ASCII {
+		overdue = " OVERDUE"
+	}
+	for i, task := range tasks {
+		status := iconDone
 		if !task.Done {
-			done = "❌"
+			status = iconPending
+		}
+		done := status.emoji
+		if style.ASCII {
+			done = status.text
 		}
 		createdAt := task.CreatedAt.Format("2006-01-02 15:04:05")
-		completedAt := "N/A"
-		if task.CompletedAt != nil {
-			completedAt = task.CompletedAt.Format("2006-01-02 15:04:05")
+		if style.Numbered {
+			fmt.Printf("#%d ", i+1)
 		}
-		fmt.Printf("%v %v - %v\n", done, task.ID, task.Title)
-		fmt.Printf("Description: %v\n", task.Description)
+		fmt.Printf("%v %v - %v%v\n", done, task.ID, tagIcons(task.Tags, style.TagIcons), task.Title)
+		fmt.Printf("Description: %v\n", truncateText(task.Description, style.MaxDescWidth))
 		fmt.Printf("Created At: %v\n", createdAt)
-		fmt.Printf("Completed At: %v\n", completedAt)
+		fmt.Printf("Completed At: %v\n", formatCompletedAt(task.CompletedAt))
+		if task.DueDate != nil {
+			marker := ""
+			if task.IsOverdue(now) {
+				marker = overdue
+			}
+			fmt.Printf("Due: %v%v\n", task.DueDate.Local().Format(models.DueDisplayLayout), marker)
+		}
 		fmt.Println("--------------------------------")
 	}
 }
+
+// truncateText shortens s to at most width characters, ending it with an
+// ellipsis when cut. A width of 0 or less leaves s untouched.
+func truncateText(s string, width int) string {
+	runes := []rune(s)
+	if width <= 0 || len(runes) <= width {
+		return s
+	}
+	return string(runes[:width-1]) + "…"
+}
+
+// tagIcons returns the icons of the given tags followed by a space, or an
+// empty string when none of them has one
+func tagIcons(tags []string, icons map[string]string) string {
+	var prefix []string
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		icon := strings.TrimSpace(icons[tag])
+		if icon == "" || seen[icon] {
+			continue
+		}
+		seen[icon] = true
+		prefix = append(prefix, icon)
+	}
+	if len(prefix) == 0 {
+		return ""
+	}
+	return strings.Join(prefix, "") + " "
+}
+
+// printTasksJSONLines writes each task as a single-line JSON object, without
+// any other decoration, so the output can be streamed into tools like jq
+func printTasksJSONLines(tasks []models.Task, omitEmpty bool) error {
+	encoder := json.NewEncoder(os.Stdout)
+	for _, task := range tasks {
+		value, err := taskJSONValue(task, omitEmpty)
+		if err != nil {
+			return err
+		}
+		if err := encoder.Encode(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// printTasksJSON prints the tasks as one JSON array, compact or indented
+func printTasksJSON(tasks []models.Task, indent, omitEmpty bool) error {
+	values := make([]interface{}, 0, len(tasks))
+	for _, task := range tasks {
+		value, err := taskJSONValue(task, omitEmpty)
+		if err != nil {
+			return err
+		}
+		values = append(values, value)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	if indent {
+		encoder.SetIndent("", "  ")
+	}
+	return encoder.Encode(values)
+}
+
+// taskJSONValue returns the value encoded for a task. With omitEmpty, fields
+// holding an empty string, null or an empty list are dropped; otherwise the
+// task is encoded as is.
+func taskJSONValue(task models.Task, omitEmpty bool) (interface{}, error) {
+	if !omitEmpty {
+		return task, nil
+	}
+
+	data, err := json.Marshal(task)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for name, value := range fields {
+		switch string(value) {
+		case `""`, "null", "[]":
+			delete(fields, name)
+		}
+	}
+	return fields, nil
+}
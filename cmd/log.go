@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+)
+
+// Log formats accepted by --log-format
+const (
+	logFormatText = "text"
+	logFormatJSON = "json"
+)
+
+// logFormat selects how informational and diagnostic messages are written.
+// It doesn't affect task data output such as list --json.
+var logFormat = logFormatText
+
+// validateLogFormat rejects unknown --log-format values
+func validateLogFormat() error {
+	if logFormat != logFormatText && logFormat != logFormatJSON {
+		return fmt.Errorf("invalid --log-format %q, expected text or json", logFormat)
+	}
+	return nil
+}
+
+// jsonLogger writes one JSON object per message to stdout. It is created per
+// message so it always writes to the current os.Stdout.
+func jsonLogger() *slog.Logger {
+	return slog.New(slog.NewJSONHandler(os.Stdout, nil))
+}
+
+// logInfo reports that something was done, about the task with taskID (0 when
+// it concerns no single task). Text logs read "✓ <message>"; JSON logs carry
+// the level, the message and the task id.
+func logInfo(taskID int, format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	if logFormat == logFormatJSON {
+		jsonLogger().Info(msg, taskAttrs(taskID)...)
+		return
+	}
+	fmt.Printf("%s %s\n", iconSuccess, msg)
+}
+
+// logWarning reports a non-fatal problem, as "⚠️  Warning: <message>" in text
+// logs or as a JSON object with level WARN
+func logWarning(msg string) {
+	if logFormat == logFormatJSON {
+		jsonLogger().Warn(msg)
+		return
+	}
+	fmt.Printf("%s Warning: %s\n", iconWarning, msg)
+}
+
+// taskAttrs returns the log attributes identifying a task, if any
+func taskAttrs(taskID int) []any {
+	if taskID == 0 {
+		return nil
+	}
+	return []any{"task_id", taskID}
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/eduardamirelly/tasker/database"
+	"github.com/eduardamirelly/tasker/models"
+	"github.com/spf13/cobra"
+)
+
+var nextCmd = &cobra.Command{
+	Use:   "next",
+	Short: "Show the pending task to work on next",
+	Long: `Show the single pending task to work on next.
+
+Tasks with a due date come first, the most overdue or soonest due first.
+Among tasks due at the same time, or without a due date, higher priority
+comes first, then the oldest task.
+
+tasker done --chain uses the same rule to show what comes next after
+completing a task.
+
+Examples:
+  tasker next
+  tasker done 3 --chain`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		task, err := nextTask()
+		if err != nil {
+			fmt.Printf("Error finding the next task: %v\n", err)
+			return
+		}
+		printNextTask(task)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(nextCmd)
+}
+
+// nextOrder ranks pending tasks for next: soonest due first, then by
+// priority from high to low, then oldest first
+var nextOrder = []string{
+	"due_date IS NULL ASC",
+	"datetime(due_date) ASC",
+	sortColumns["priority"] + " DESC",
+	"datetime(created_at) ASC",
+}
+
+// nextTask returns the pending task to work on next, or nil when every task is done
+func nextTask() (*models.Task, error) {
+	pending := false
+	where, args := taskFilter{Done: &pending}.whereClause()
+	query := `SELECT ` + taskColumns + ` FROM tasks` + where + orderClause(nextOrder...) + ` LIMIT 1`
+
+	task, err := scanTask(database.GetDB().QueryRow(query, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := []models.Task{task}
+	if err := attachTags(tasks); err != nil {
+		return nil, err
+	}
+	return &tasks[0], nil
+}
+
+// printNextTask prints the task to work on next, or that nothing is pending
+func printNextTask(task *models.Task) {
+	if task == nil {
+		fmt.Println("No pending tasks, all done!")
+		return
+	}
+	fmt.Printf("Next up: %d - %s\n", task.ID, task.Title)
+	printTask(task)
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/eduardamirelly/tasker/links"
+	"github.com/spf13/cobra"
+)
+
+var openCmd = &cobra.Command{
+	Use:   "open [id]",
+	Short: "Open the first URL found in a task",
+	Long: `Open the first URL found in a task's title or description in the
+default browser.
+
+Examples:
+  tasker open 3`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		id := args[0]
+
+		task, err := findTaskById(id)
+		if err != nil {
+			fmt.Printf("Error finding task: %v\n", err)
+			return
+		}
+
+		if task == nil {
+			fmt.Printf("%s Task not found: %s\n", iconFailed, id)
+			return
+		}
+
+		url, err := links.OpenFirst(opener, task.Title, task.Description)
+		if errors.Is(err, links.ErrNoURL) {
+			fmt.Printf("%s No URL found in task %d: %s\n", iconFailed, task.ID, task.Title)
+			return
+		}
+		if err != nil {
+			fmt.Printf("Error opening %s: %v\n", url, err)
+			return
+		}
+
+		fmt.Printf("%s Opening %s\n", iconSuccess, url)
+	},
+}
+
+// opener opens the URL found by open
+var opener links.Opener = links.SystemOpener
+
+// SetOpener overrides how open opens URLs and returns the previous opener, so
+// tests can record the URLs instead of starting a browser
+func SetOpener(o links.Opener) links.Opener {
+	previous := opener
+	opener = o
+	return previous
+}
+
+func init() {
+	rootCmd.AddCommand(openCmd)
+}
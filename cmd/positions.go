@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eduardamirelly/tasker/database"
+	"github.com/eduardamirelly/tasker/models"
+)
+
+// lastListKey is the app_state key holding the tasks shown by the last list
+const lastListKey = "last_list"
+
+// listSnapshot records which tasks the last list showed, in display order,
+// so they can be referred to by position. Count and MaxID capture the tasks
+// table at the time, to detect tasks added or removed since. Hash
+// fingerprints the listed tasks themselves (see listHash).
+type listSnapshot struct {
+	IDs   []int  `json:"ids"`
+	Count int    `json:"count"`
+	MaxID int    `json:"max_id"`
+	Hash  string `json:"hash,omitempty"`
+}
+
+// tableFingerprint returns the number of tasks and the highest id in use
+func tableFingerprint() (int, int, error) {
+	var count, maxID int
+	err := database.GetDB().QueryRow(`SELECT COUNT(*), COALESCE(MAX(id), 0) FROM tasks`).Scan(&count, &maxID)
+	return count, maxID, err
+}
+
+// saveListPositions remembers the order tasks were listed in
+func saveListPositions(tasks []models.Task) error {
+	count, maxID, err := tableFingerprint()
+	if err != nil {
+		return err
+	}
+
+	snapshot := listSnapshot{IDs: make([]int, len(tasks)), Count: count, MaxID: maxID, Hash: listHash(tasks)}
+	for i, task := range tasks {
+		snapshot.IDs[i] = task.ID
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	err = database.SetState(lastListKey, string(data))
+	if database.IsReadOnly(err) {
+		// A read-only database can still be listed, #N just won't refer to it
+		return nil
+	}
+	return err
+}
+
+// isPosition reports whether ref refers to a task by its position in the last
+// list, written "#2"
+func isPosition(ref string) bool {
+	return strings.HasPrefix(ref, "#")
+}
+
+// resolvePosition returns the id of the task shown at position ref ("#2") by
+// the last list. It refuses when tasks were added or removed since, as the
+// positions may then no longer match what a new list would show.
+func resolvePosition(ref string) (int, error) {
+	snapshot, position, err := lastListPosition(ref)
+	if err != nil {
+		return 0, err
+	}
+	return snapshot.IDs[position-1], nil
+}
+
+// resolvePositionStrict is resolvePosition for done --at-position: it also
+// refuses when any listed task changed since, e.g. was edited or completed,
+// since that may reorder the list or drop tasks from it
+func resolvePositionStrict(ref string) (int, error) {
+	snapshot, position, err := lastListPosition(ref)
+	if err != nil {
+		return 0, err
+	}
+	if snapshot.Hash == "" {
+		return 0, fmt.Errorf("the last list was saved by an older version of tasker, run tasker list again")
+	}
+
+	tasks, err := listTasks(taskFilter{IDs: snapshot.IDs})
+	if err != nil {
+		return 0, err
+	}
+	byID := make(map[int]models.Task, len(tasks))
+	for _, task := range tasks {
+		byID[task.ID] = task
+	}
+	listed := make([]models.Task, 0, len(snapshot.IDs))
+	for _, id := range snapshot.IDs {
+		listed = append(listed, byID[id])
+	}
+	if listHash(listed) != snapshot.Hash {
+		return 0, fmt.Errorf("the listed tasks changed since the last list, run tasker list again to see their current positions")
+	}
+	return snapshot.IDs[position-1], nil
+}
+
+// lastListPosition parses position ref ("#2") and returns it along with the
+// last list, which must still match the tasks table and show that position
+func lastListPosition(ref string) (listSnapshot, int, error) {
+	var snapshot listSnapshot
+	position, err := strconv.Atoi(strings.TrimPrefix(ref, "#"))
+	if err != nil || position < 1 {
+		return snapshot, 0, fmt.Errorf("invalid position %q, expected e.g. #2", ref)
+	}
+
+	value, ok, err := database.GetState(lastListKey)
+	if err != nil {
+		return snapshot, 0, err
+	}
+	if !ok {
+		return snapshot, 0, fmt.Errorf("no list to take position %s from, run tasker list first", ref)
+	}
+
+	if err := json.Unmarshal([]byte(value), &snapshot); err != nil {
+		return snapshot, 0, fmt.Errorf("invalid saved list: %w", err)
+	}
+
+	count, maxID, err := tableFingerprint()
+	if err != nil {
+		return snapshot, 0, err
+	}
+	if count != snapshot.Count || maxID != snapshot.MaxID {
+		return snapshot, 0, fmt.Errorf("tasks were added or removed since the last list, run tasker list again")
+	}
+
+	if position > len(snapshot.IDs) {
+		return snapshot, 0, fmt.Errorf("position %s is out of range, the last list showed %d task(s)", ref, len(snapshot.IDs))
+	}
+	return snapshot, position, nil
+}
+
+// listHash fingerprints tasks in the order they were listed, over the fields
+// deciding whether and where list shows a task. A task missing since (the
+// zero value) changes the hash too.
+func listHash(tasks []models.Task) string {
+	hash := sha256.New()
+	for _, task := range tasks {
+		fmt.Fprintf(hash, "%d\x00%s\x00%t\x00%s\x00%s\x00%s\x00%s\x00%s\x00%s\n",
+			task.ID, task.Title, task.Done, task.Priority, strings.Join(task.Tags, ","),
+			hashTime(&task.CreatedAt), hashTime(task.DueDate), hashTime(task.CompletedAt), hashTime(task.UpdatedAt))
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// hashTime formats t for listHash, as an empty string when it is not set
+func hashTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/eduardamirelly/tasker/webhook"
+	"github.com/spf13/cobra"
+)
+
+var renameCmd = &cobra.Command{
+	Use:   "rename [id] [new title]",
+	Short: "Change the title of a task",
+	Long: `Change only the title of a task, a shorthand for edit --title.
+
+Examples:
+  tasker rename 3 "Buy groceries and snacks"`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		id := args[0]
+
+		title := strings.TrimSpace(args[1])
+		if title == "" {
+			fmt.Printf("Error renaming task: title cannot be empty\n")
+			return
+		}
+
+		task, err := findTaskById(id)
+		if err != nil {
+			fmt.Printf("Error finding task: %v\n", err)
+			return
+		}
+
+		if task == nil {
+			fmt.Printf("%s Task not found: %s\n", iconFailed, id)
+			return
+		}
+
+		updated := *task
+		updated.Title = title
+
+		changes := diffTasks(*task, updated)
+		if len(changes) == 0 {
+			fmt.Printf("Nothing to update for task %d\n", task.ID)
+			return
+		}
+
+		if err := updateTask(updated); err != nil {
+			fmt.Printf("Error renaming task: %v\n", err)
+			return
+		}
+
+		logInfo(updated.ID, "Task renamed: %s", updated.Title)
+		notify(webhook.TaskUpdated, updated, changes)
+		printChanges(changes)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(renameCmd)
+}
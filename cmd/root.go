@@ -2,44 +2,164 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/eduardamirelly/tasker/database"
+	"github.com/eduardamirelly/tasker/progress"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
+// version is the tasker release, set at build time with
+// -ldflags "-X github.com/eduardamirelly/tasker/cmd.version=v1.2.3"
+var version = "dev"
+
+// quiet suppresses non-essential output such as progress indicators
+var quiet bool
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
-	Use:   "tasker",
-	Short: "A simple CLI task manager",
+	Use:     "tasker",
+	Short:   "A simple CLI task manager",
+	Version: version,
 	Long: `Tasker is a command-line task management tool that helps you:
 - Add new tasks
 - List all tasks  
-- Mark tasks as done
-- Export tasks to CSV
+- Edit or rename tasks
+- Mark tasks as done and see which task to work on next
+- Tag tasks and attach files or URLs to them
+- Show the details of a task
+- Show statistics about your tasks and progress toward completion goals
+- Save frequently used command lines as aliases
+- Export tasks to CSV, JSON or SQL, verify exports and import them from CSV or JSON
+
+Store your tasks locally in a SQLite database, tasker.db in the working
+directory unless --db or $TASKER_DB names another file (see tasker where).
+
+Flag defaults can be set per command in a JSON config file
+($TASKER_CONFIG, or tasker/config.json in your user config directory).
 
-Store your tasks locally in a SQLite database.`,
+When $TASKER_WEBHOOK (or "webhook" in the config file) holds a URL, every
+task added, imported, completed, reopened, edited or renamed is posted to it
+as a JSON event.
+Delivery is best effort: failures, and an invalid URL, are reported as
+warnings.
+
+--no-emoji, or setting $TASKER_NO_EMOJI, prints plain text such as [x] and !
+instead of emoji, for terminals that can't show them.`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := applyConfigDefaults(cmd); err != nil {
+			return err
+		}
+		if err := validateLogFormat(); err != nil {
+			return err
+		}
+		if _, ok := cmd.Annotations[noDatabaseAnnotation]; !ok {
+			if err := openDatabase(); err != nil {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("initializing database: %w", err)
+			}
+		}
+		return setupWebhook()
+	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
-	// Initialize database
-	if err := database.InitDB(); err != nil {
-		fmt.Printf("Error initializing database: %v\n", err)
+	// The database is opened before running a command (see openDatabase) and
+	// must be closed when the program exits
+	defer database.CloseDB()
+
+	args, err := expandAlias(os.Args[1:])
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
 		os.Exit(1)
 	}
+	rootCmd.SetArgs(args)
 
-	// Ensure database is closed when program exits
-	defer database.CloseDB()
-
-	err := rootCmd.Execute()
+	err = rootCmd.Execute()
+	flushWebhook()
 	if err != nil {
+		database.CloseDB()
 		os.Exit(1)
 	}
 }
 
+// ExecuteArgs runs the root command with the given arguments against the
+// already initialized database. Flags are reset to their defaults first so
+// repeated calls (as done by the test suite) don't leak state between runs.
+func ExecuteArgs(args ...string) error {
+	resetFlags(rootCmd)
+	args, err := expandAlias(args)
+	if err != nil {
+		return err
+	}
+	rootCmd.SetArgs(args)
+	defer flushWebhook()
+	return rootCmd.Execute()
+}
+
+// stdoutIsTerminal reports whether output goes to a terminal rather than a
+// pipe or file, for defaults meant only for people reading the output
+var stdoutIsTerminal = func() bool {
+	return progress.IsTerminal(os.Stdout)
+}
+
+// SetStdoutTerminal overrides the terminal detection of stdout and returns the
+// previous detector, so tests can exercise the interactive defaults
+func SetStdoutTerminal(isTerminal func() bool) func() bool {
+	previous := stdoutIsTerminal
+	stdoutIsTerminal = isTerminal
+	return previous
+}
+
+// inputIsTerminal reports whether in, the input of a command, is a terminal
+// someone can answer prompts on rather than a pipe or file
+var inputIsTerminal = func(in io.Reader) bool {
+	f, ok := in.(*os.File)
+	return ok && progress.IsTerminal(f)
+}
+
+// SetInputTerminal overrides the terminal detection of command input and
+// returns the previous detector, so tests can answer confirmation prompts
+func SetInputTerminal(isTerminal func(io.Reader) bool) func(io.Reader) bool {
+	previous := inputIsTerminal
+	inputIsTerminal = isTerminal
+	return previous
+}
+
+// SetIn sets the reader commands read user input from; nil restores stdin.
+// Tests use it to simulate answers to interactive prompts.
+func SetIn(r io.Reader) {
+	rootCmd.SetIn(r)
+}
+
+// resetFlags restores every flag of cmd and its subcommands to its default value
+func resetFlags(cmd *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			sv.Replace(nil)
+		} else {
+			f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	}
+	cmd.Flags().VisitAll(reset)
+	cmd.PersistentFlags().VisitAll(reset)
+	for _, sub := range cmd.Commands() {
+		resetFlags(sub)
+	}
+}
+
 func init() {
+	rootCmd.PersistentFlags().BoolVar(&strict, "strict", false, "Treat warnings (duplicate title, long description, due date in the past) as errors")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Suppress non-essential output such as progress indicators")
+	rootCmd.PersistentFlags().StringVar(&dbPath, "db", "", "Database file (default: $TASKER_DB, the config file, or tasker.db in the working directory)")
+	rootCmd.PersistentFlags().BoolVar(&noEmoji, "no-emoji", false, "Print plain text instead of emoji, for terminals that can't show them (or set $TASKER_NO_EMOJI)")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", logFormatText, "Format of informational and warning messages: text or json")
+
 	// Here you will define your flags and configuration settings.
 	// Cobra supports persistent flags, which, if defined here,
 	// will be global for your application.
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var showCmd = &cobra.Command{
+	Use:   "show [id]",
+	Short: "Show the details of a task",
+	Long: `Show all the details of a single task, including its completion note.
+
+Examples:
+  tasker show 3
+  tasker show 3 --pretty`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		id := args[0]
+
+		task, err := findTaskById(id)
+		if err != nil {
+			fmt.Printf("Error finding task: %v\n", err)
+			return
+		}
+
+		if task == nil {
+			fmt.Printf("%s Task not found: %s\n", iconFailed, id)
+			return
+		}
+
+		printTask(task)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(showCmd)
+
+	showCmd.Flags().BoolVar(&prettyDetails, "pretty", false, "Print task details as an aligned block with every field")
+}
@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/eduardamirelly/tasker/dates"
+	"github.com/eduardamirelly/tasker/models"
+	"github.com/spf13/cobra"
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show statistics about your tasks",
+	Long: `Show how many tasks there are, how many are done, pending and overdue, and
+how long tasks take to complete on average.
+
+With --summary-only everything is printed on a single terse line, suitable for
+a shell prompt or status bar.
+
+--from and --to scope the report to the tasks completed within that period,
+for monthly or quarterly reviews: how many were completed and how long they
+took on average. They take a date (--to includes the whole day), a date and
+time, today, yesterday or a time ago such as 30d, 2w or 12h.
+
+Examples:
+  tasker stats
+  tasker stats --summary-only
+  tasker stats --from 2024-03-01 --to 2024-03-31
+  tasker stats --from 30d`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		now := time.Now()
+		var period statsPeriod
+		if from, _ := cmd.Flags().GetString("from"); from != "" {
+			start, err := dates.ParseSince(from, now)
+			if err != nil {
+				fmt.Printf("Error computing stats: invalid --from: %v\n", err)
+				return
+			}
+			period.From = &start
+		}
+		if to, _ := cmd.Flags().GetString("to"); to != "" {
+			end, err := dates.ParseUntil(to, now)
+			if err != nil {
+				fmt.Printf("Error computing stats: invalid --to: %v\n", err)
+				return
+			}
+			period.To = &end
+		}
+		if period.From != nil && period.To != nil && !period.From.Before(*period.To) {
+			fmt.Printf("Error computing stats: --from must be before --to\n")
+			return
+		}
+
+		tasks, err := listTasks(taskFilter{CompletedFrom: period.From, CompletedTo: period.To})
+		if err != nil {
+			fmt.Printf("Error computing stats: %v\n", err)
+			return
+		}
+
+		stats := computeStats(tasks, now)
+		summaryOnly, _ := cmd.Flags().GetBool("summary-only")
+		switch {
+		case period.set() && summaryOnly:
+			fmt.Printf("%d done, avg %s (%s)\n", stats.Done, stats.averageCompletion(true), period)
+		case period.set():
+			fmt.Printf("Period:          %s\n", period)
+			fmt.Printf("Completed:       %d\n", stats.Done)
+			fmt.Printf("Avg completion:  %s\n", stats.averageCompletion(false))
+		case summaryOnly:
+			fmt.Println(stats.summary())
+		default:
+			stats.print()
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+
+	statsCmd.Flags().Bool("summary-only", false, `Print a single line such as "10 tasks, 40% done, avg 2d, 3 overdue"`)
+	statsCmd.Flags().String("from", "", `Only count tasks completed from this date or time ago ("2024-03-01", "30d")`)
+	statsCmd.Flags().String("to", "", `Only count tasks completed up to this date, included, or time ago`)
+}
+
+// statsPeriod is the half-open window [From, To) of completions stats
+// reports on; nil bounds are open
+type statsPeriod struct {
+	From *time.Time
+	To   *time.Time
+}
+
+// set reports whether the period restricts anything
+func (p statsPeriod) set() bool {
+	return p.From != nil || p.To != nil
+}
+
+// String describes the period, e.g. "2024-03-01 00:00 to 2024-04-01 00:00"
+func (p statsPeriod) String() string {
+	const layout = "2006-01-02 15:04"
+	switch {
+	case p.From != nil && p.To != nil:
+		return p.From.Local().Format(layout) + " to " + p.To.Local().Format(layout)
+	case p.From != nil:
+		return "since " + p.From.Local().Format(layout)
+	case p.To != nil:
+		return "before " + p.To.Local().Format(layout)
+	}
+	return "all time"
+}
+
+// taskStats aggregates a set of tasks
+type taskStats struct {
+	Total   int
+	Done    int
+	Overdue int
+	// AverageCompletion is the mean time from creation to completion of the
+	// done tasks; zero when no task has a completion time
+	AverageCompletion time.Duration
+}
+
+// computeStats aggregates tasks, counting as overdue the pending tasks whose
+// due date is before now
+func computeStats(tasks []models.Task, now time.Time) taskStats {
+	var stats taskStats
+	var completionTotal time.Duration
+	var completed int
+
+	for _, task := range tasks {
+		stats.Total++
+		if task.IsOverdue(now) {
+			stats.Overdue++
+		}
+		if !task.Done {
+			continue
+		}
+		stats.Done++
+		if task.CompletedAt != nil && !task.CompletedAt.IsZero() {
+			completionTotal += task.CompletedAt.Sub(task.CreatedAt)
+			completed++
+		}
+	}
+
+	if completed > 0 {
+		stats.AverageCompletion = completionTotal / time.Duration(completed)
+	}
+	return stats
+}
+
+// donePercent is the share of done tasks, rounded to a whole percentage
+func (s taskStats) donePercent() int {
+	if s.Total == 0 {
+		return 0
+	}
+	return (s.Done*100 + s.Total/2) / s.Total
+}
+
+// averageCompletion renders the average completion time, or "n/a" when no
+// task was completed. With short set only the largest unit is kept.
+func (s taskStats) averageCompletion(short bool) string {
+	if s.AverageCompletion <= 0 {
+		return "n/a"
+	}
+	if !short {
+		return formatDuration(s.AverageCompletion)
+	}
+	if s.AverageCompletion < time.Minute {
+		return "<1m"
+	}
+	return strings.Fields(formatDuration(s.AverageCompletion))[0]
+}
+
+// summary renders the stats on one line, e.g. "10 tasks, 40% done, avg 2d, 3 overdue"
+func (s taskStats) summary() string {
+	return fmt.Sprintf("%d tasks, %d%% done, avg %s, %d overdue",
+		s.Total, s.donePercent(), s.averageCompletion(true), s.Overdue)
+}
+
+// print writes the full stats report
+func (s taskStats) print() {
+	fmt.Printf("Total tasks:     %d\n", s.Total)
+	fmt.Printf("Done:            %d (%d%%)\n", s.Done, s.donePercent())
+	fmt.Printf("Pending:         %d\n", s.Total-s.Done)
+	fmt.Printf("Overdue:         %d\n", s.Overdue)
+	fmt.Printf("Avg completion:  %s\n", s.averageCompletion(false))
+}
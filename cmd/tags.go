@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/eduardamirelly/tasker/database"
+	"github.com/eduardamirelly/tasker/models"
+	"github.com/spf13/cobra"
+)
+
+var tagsCmd = &cobra.Command{
+	Use:   "tags",
+	Short: "List the tags in use",
+	Long: `List every tag used by at least one task, with the number of tasks
+carrying it, most used first.
+
+Tags are added with tasker add --tag.
+
+Examples:
+  tasker tags`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		counts, err := tagCounts()
+		if err != nil {
+			fmt.Printf("Error listing tags: %v\n", err)
+			return
+		}
+
+		if len(counts) == 0 {
+			fmt.Println("No tags in use")
+			return
+		}
+
+		for _, count := range counts {
+			fmt.Printf("%-20s %d\n", count.Tag, count.Tasks)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(tagsCmd)
+}
+
+// tagCount is the number of tasks carrying a tag
+type tagCount struct {
+	Tag   string
+	Tasks int
+}
+
+// tagCounts returns the tags in use ordered by frequency, then name
+func tagCounts() ([]tagCount, error) {
+	query := `SELECT tag, COUNT(*) FROM task_tags
+		JOIN tasks ON tasks.id = task_tags.task_id
+		GROUP BY tag ORDER BY COUNT(*) DESC, tag ASC`
+	rows, err := database.GetDB().Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var counts []tagCount
+	for rows.Next() {
+		var count tagCount
+		if err := rows.Scan(&count.Tag, &count.Tasks); err != nil {
+			return nil, err
+		}
+		counts = append(counts, count)
+	}
+	return counts, rows.Err()
+}
+
+// normalizeTags lowercases and trims tags, dropping empty ones and repeats.
+// Tags cannot contain whitespace or commas.
+func normalizeTags(tags []string) ([]string, error) {
+	var normalized []string
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(tag), "#")))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if strings.ContainsAny(tag, " \t\n,") {
+			return nil, fmt.Errorf("invalid tag %q: tags cannot contain spaces or commas", tag)
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
+
+// saveTags stores the tags of a task within tx
+func saveTags(tx *sql.Tx, taskID int64, tags []string) error {
+	for _, tag := range tags {
+		if _, err := tx.Exec(`INSERT OR IGNORE INTO task_tags (task_id, tag) VALUES (?, ?)`, taskID, tag); err != nil {
+			return fmt.Errorf("failed to save tag %q: %w", tag, err)
+		}
+	}
+	return nil
+}
+
+// tagQueryBatch is the number of task ids looked up per tag query, well
+// below the number of parameters SQLite accepts in one statement
+const tagQueryBatch = 500
+
+// attachTags loads the tags of the given tasks into their Tags field
+func attachTags(tasks []models.Task) error {
+	index := make(map[int]int, len(tasks))
+	for i, task := range tasks {
+		index[task.ID] = i
+	}
+
+	for start := 0; start < len(tasks); start += tagQueryBatch {
+		batch := tasks[start:min(start+tagQueryBatch, len(tasks))]
+		if err := attachTagsBatch(tasks, index, batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// attachTagsBatch loads the tags of the tasks in batch into tasks, found
+// through index by task id
+func attachTagsBatch(tasks []models.Task, index map[int]int, batch []models.Task) error {
+	placeholders := make([]string, len(batch))
+	args := make([]interface{}, len(batch))
+	for i, task := range batch {
+		placeholders[i] = "?"
+		args[i] = task.ID
+	}
+
+	query := `SELECT task_id, tag FROM task_tags WHERE task_id IN (` + strings.Join(placeholders, ", ") + `) ORDER BY tag`
+	rows, err := database.GetDB().Query(query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			taskID int
+			tag    string
+		)
+		if err := rows.Scan(&taskID, &tag); err != nil {
+			return err
+		}
+		if i, ok := index[taskID]; ok {
+			tasks[i].Tags = append(tasks[i].Tags, tag)
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/eduardamirelly/tasker/models"
+)
+
+// taskColumns is the column list selected whenever a full task is loaded.
+// Keep it in sync with scanTask.
+const taskColumns = `id, uuid, title, description, done, created_at, completed_at, completion_note, due_date, updated_at, priority`
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanTask reads a task selected with taskColumns
+func scanTask(row rowScanner) (models.Task, error) {
+	var task models.Task
+	var uuid, priority sql.NullString
+	err := row.Scan(&task.ID, &uuid, &task.Title, &task.Description, &task.Done, &task.CreatedAt, &task.CompletedAt, &task.CompletionNote, &task.DueDate, &task.UpdatedAt, &priority)
+	task.UUID = uuid.String
+	task.Priority = priority.String
+	return task, err
+}
+
+// defaultOrder sorts tasks chronologically by creation time
+const defaultOrder = "datetime(created_at) ASC"
+
+// sortColumns maps the accepted sort keys to the SQL expression they order by
+var sortColumns = map[string]string{
+	"id":        "id",
+	"created":   "datetime(created_at)",
+	"completed": "datetime(completed_at)",
+	"due":       "datetime(due_date)",
+	"title":     "title COLLATE NOCASE",
+	"priority":  "CASE priority WHEN 'low' THEN 1 WHEN 'medium' THEN 2 WHEN 'high' THEN 3 END", // low to high
+}
+
+// nullableSortKeys are the sort keys whose column may be NULL (pending tasks
+// have no completion time, tasks without a deadline no due date)
+var nullableSortKeys = map[string]bool{
+	"completed": true,
+	"due":       true,
+}
+
+// taskSort describes how listed tasks are ordered
+type taskSort struct {
+	// Key is one of sortColumns, or several separated by commas to break ties
+	// of the first with the next ones; empty means the default order
+	Key     string
+	Reverse bool
+	// Nulls places tasks without a value for a nullable key "first" or
+	// "last"; empty means last. It holds regardless of Reverse.
+	Nulls string
+}
+
+// sortKeys returns the accepted sort keys in alphabetical order
+func sortKeys() []string {
+	keys := make([]string, 0, len(sortColumns))
+	for key := range sortColumns {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// keys returns the sort keys in order of precedence
+func (s taskSort) keys() []string {
+	if s.Key == "" {
+		return nil
+	}
+	keys := strings.Split(s.Key, ",")
+	for i, key := range keys {
+		keys[i] = strings.TrimSpace(key)
+	}
+	return keys
+}
+
+// validate rejects sort keys that aren't in sortColumns, so user input never
+// reaches the SQL
+func (s taskSort) validate() error {
+	if s.Nulls != "" && s.Nulls != "first" && s.Nulls != "last" {
+		return fmt.Errorf("invalid nulls placement %q (valid: first, last)", s.Nulls)
+	}
+	for _, key := range s.keys() {
+		if _, ok := sortColumns[key]; !ok {
+			return fmt.Errorf("invalid sort key %q (valid: %s)", key, strings.Join(sortKeys(), ", "))
+		}
+	}
+	return nil
+}
+
+// orderClause builds the ORDER BY clause for the sort. Reverse and Nulls
+// apply to every key.
+func (s taskSort) orderClause() string {
+	direction := " ASC"
+	if s.Reverse {
+		direction = " DESC"
+	}
+
+	var exprs []string
+	for _, key := range s.keys() {
+		column, ok := sortColumns[key]
+		if !ok {
+			continue
+		}
+		if nullableSortKeys[key] {
+			// "x IS NULL" is 1 for missing values, so ascending puts them last
+			nulls := column + " IS NULL ASC"
+			if s.Nulls == "first" {
+				nulls = column + " IS NULL DESC"
+			}
+			exprs = append(exprs, nulls)
+		}
+		exprs = append(exprs, column+direction)
+	}
+	if len(exprs) == 0 {
+		exprs = append(exprs, strings.TrimSuffix(defaultOrder, " ASC")+direction)
+	}
+	return orderClause(exprs...)
+}
+
+// orderClause builds an ORDER BY clause from the given sort expressions. The id
+// is always appended as a final tiebreaker so tasks sharing a sort value (e.g. a
+// batch inserted with the same created_at) come back in a stable order.
+func orderClause(sortExprs ...string) string {
+	return " ORDER BY " + strings.Join(append(sortExprs, "id ASC"), ", ")
+}
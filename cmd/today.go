@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/eduardamirelly/tasker/dates"
+	"github.com/eduardamirelly/tasker/models"
+	"github.com/spf13/cobra"
+)
+
+var todayCmd = &cobra.Command{
+	Use:   "today",
+	Short: "Show tasks created and completed today",
+	Long: `Show the tasks created today and the tasks completed today in two
+sections with counts, handy for daily standups. Completed tasks are shown in
+the order they were completed, tasks completed together in the order they
+were added.
+
+Examples:
+  tasker today`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		created, completed, err := todayTasks(time.Now())
+		if err != nil {
+			fmt.Printf("Error listing today's tasks: %v\n", err)
+			return
+		}
+
+		printTodaySection("Created today", created)
+		fmt.Println()
+		printTodaySection("Completed today", completed)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(todayCmd)
+}
+
+// todayTasks returns the tasks created and the tasks completed on the local
+// day containing now, the latter in the order they were completed
+func todayTasks(now time.Time) ([]models.Task, []models.Task, error) {
+	start, end := dates.DayBounds(now)
+
+	created, err := listTasks(taskFilter{CreatedFrom: &start, CreatedTo: &end})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	completed, err := listTasksSorted(taskFilter{CompletedFrom: &start, CompletedTo: &end}, taskSort{Key: "completed"})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return created, completed, nil
+}
+
+func printTodaySection(title string, tasks []models.Task) {
+	fmt.Printf("%s (%d):\n", title, len(tasks))
+	if len(tasks) == 0 {
+		fmt.Println("  None")
+		return
+	}
+	printTasks(tasks)
+}
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/eduardamirelly/tasker/config"
+	"github.com/eduardamirelly/tasker/models"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// helpTopic is a conceptual guide printed by help-topics
+type helpTopic struct {
+	Name    string
+	Summary string
+	Body    func() string
+}
+
+// helpTopics lists the guides in the order they are shown
+var helpTopics = []helpTopic{
+	{"filters", "Flags selecting which tasks list, done and check work on", filtersTopic},
+	{"formats", "Export and import formats", formatsTopic},
+	{"dates", "Accepted due date and time expressions", datesTopic},
+	{"config", "Setting flag defaults in the config file", configTopic},
+}
+
+var helpTopicsCmd = &cobra.Command{
+	Use:    "help-topics [topic]",
+	Short:  "Show guides on topics spanning several commands",
+	Hidden: true,
+	Args:   cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			fmt.Println("Available help topics:")
+			for _, topic := range helpTopics {
+				fmt.Printf("  %-10s %s\n", topic.Name, topic.Summary)
+			}
+			fmt.Println()
+			fmt.Println(`Run "tasker help-topics <topic>" to read one.`)
+			return
+		}
+
+		for _, topic := range helpTopics {
+			if topic.Name == args[0] {
+				fmt.Print(topic.Body())
+				return
+			}
+		}
+
+		names := make([]string, len(helpTopics))
+		for i, topic := range helpTopics {
+			names[i] = topic.Name
+		}
+		fmt.Printf("Unknown help topic %q. Available topics: %s\n", args[0], strings.Join(names, ", "))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(helpTopicsCmd)
+}
+
+// filtersTopic describes the shared filter flags, read from the list command
+// so the guide never drifts from the flags actually registered
+func filtersTopic() string {
+	var b strings.Builder
+	b.WriteString("Filters\n\n")
+	b.WriteString("The list, done and check commands share these filter flags. When several\n")
+	b.WriteString("are given, a task must match all of them.\n\n")
+
+	listCmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if _, ok := f.Annotations[filterAnnotation]; !ok {
+			return
+		}
+		name := "--" + f.Name
+		if f.Value.Type() != "bool" {
+			name += " " + f.Value.Type()
+		}
+		fmt.Fprintf(&b, "  %-24s %s\n", name, f.Usage)
+	})
+
+	b.WriteString(`
+done completes every pending task the filters match (after confirmation, or
+with --yes).
+
+Examples:
+  tasker list --title-contains report --overdue
+  tasker list --due-this-week --sort due
+  tasker done --title-prefix "[release]" --yes
+  tasker check --overdue-days 7 --exec 'notify-send {{.Title}}'
+`)
+	return b.String()
+}
+
+func formatsTopic() string {
+	return `Formats
+
+export writes CSV by default, with the columns:
+  ` + strings.Join(models.CSVHeader, ", ") + `
+Timestamps use "2006-01-02 15:04:05" in UTC, or RFC 3339 with nanoseconds
+with --precise. --bom prepends a UTF-8 byte order mark for Excel.
+
+--format json writes an indented JSON array of tasks; --manifest wraps it in
+an envelope with the export time, tasker version, task count and filters.
+
+--format sql writes the table definitions and INSERT statements for the tasks,
+their tags and attachments, ready to load with sqlite3.
+
+--template renders each task through a Go text/template instead, e.g.
+  tasker export -o tasks.md --template '- {{.Title}} ({{date .CreatedAt}})'
+
+import reads the CSV format back, keeping task ids and accepting both
+timestamp precisions. It also reads the JSON format, with or without the
+manifest, including priorities, due dates and tags. list --jsonl prints one
+JSON object per task for piping into tools such as jq.
+`
+}
+
+func datesTopic() string {
+	return `Dates
+
+add --due accepts:
+  2024-06-01             end of that day
+  "2024-06-01 14:30"     that exact time
+  today, tomorrow        end of the day, or at a time: "tomorrow 5pm"
+  friday, "next monday"  the next such weekday (never today)
+  "in 3 days"            also minutes, hours, weeks and months
+
+Times can be written as 5pm, "5:30 pm" or 17:30.
+
+list --modified-since accepts a date (start of that day), a date and time, or
+a duration before now such as 24h or 90m.
+
+Weeks for --due-this-week start on Monday; set TASKER_WEEK_START to change it.
+`
+}
+
+func configTopic() string {
+	path, err := config.Path()
+	if err != nil {
+		path = "(unavailable: " + err.Error() + ")"
+	}
+
+	return `Config
+
+Flag defaults can be set per command in a JSON config file, read from
+$` + config.PathEnv + ` or else:
+  ` + path + `
+
+Commands are keyed by their name and flags by their long name, with values
+written as on the command line:
+  {
+    "defaults": {
+      "list": {"sort": "due", "overdue": "true"},
+      "export": {"format": "json"}
+    }
+  }
+
+Flags given on the command line always take precedence over the config,
+which takes precedence over the built-in defaults.
+
+The "aliases" object holds the command lines saved with tasker alias save:
+  {"aliases": {"myday": "list --due-today --sort due"}}
+
+The "tag_icons" object gives tags an emoji or symbol that list prefixes the
+titles of their tasks with. Tags without one are left undecorated, and list
+--ascii or the NO_COLOR environment variable turn the icons off:
+  {"tag_icons": {"work": "💼", "home": "🏠"}}
+
+"webhook" is a URL every task added, imported, completed, reopened, edited or
+renamed is posted to as a JSON event, unless $TASKER_WEBHOOK names another
+one:
+  {"webhook": "https://example.com/tasker-events"}
+`
+}
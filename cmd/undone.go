@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/eduardamirelly/tasker/database"
+	"github.com/eduardamirelly/tasker/models"
+	"github.com/eduardamirelly/tasker/webhook"
+	"github.com/spf13/cobra"
+)
+
+var undoneCmd = &cobra.Command{
+	Use:     "undone [id]",
+	Aliases: []string{"reopen"},
+	Short:   "Mark a completed task as not done",
+	Long: `Mark a completed task as not done again, clearing its completion time
+and note. Useful after completing the wrong task by mistake.
+
+The task can be given by id, uuid or list position, as with done.
+
+Examples:
+  tasker undone 3
+  tasker reopen 3`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		id := args[0]
+		if isPosition(id) {
+			resolved, err := resolvePosition(id)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				return
+			}
+			id = strconv.Itoa(resolved)
+		}
+
+		task, err := findTaskById(id)
+		if err != nil {
+			fmt.Printf("Error finding task: %v\n", err)
+			return
+		}
+
+		if task == nil {
+			fmt.Printf("%s Task not found: %s\n", iconFailed, id)
+			return
+		}
+
+		markTaskAsUndone(task)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(undoneCmd)
+}
+
+// markTaskAsUndone reopens a completed task, clearing its completion time and
+// note
+func markTaskAsUndone(task *models.Task) {
+	if !task.Done {
+		fmt.Printf("%s Task %d is not done yet, nothing to reopen\n", iconNotDone, task.ID)
+		return
+	}
+
+	now := time.Now()
+	query := `UPDATE tasks SET done = FALSE, completed_at = NULL, completion_note = NULL, updated_at = ? WHERE id = ?`
+	if _, err := database.GetDB().Exec(query, now, task.ID); err != nil {
+		fmt.Printf("Error reopening task: %v\n", database.WriteError(err))
+		return
+	}
+
+	task.Done = false
+	task.CompletedAt = nil
+	task.CompletionNote = nil
+	task.UpdatedAt = &now
+
+	logInfo(task.ID, "Task reopened: %s", task.Title)
+	notify(webhook.TaskReopened, *task, nil)
+	printTask(task)
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/eduardamirelly/tasker/database"
+)
+
+// maxDescriptionLength is the description length above which add warns
+const maxDescriptionLength = 500
+
+// strict turns warnings into errors
+var strict bool
+
+// warn reports a non-fatal problem. In strict mode the warning is returned
+// as an error instead so the command can fail with a non-zero exit code.
+func warn(format string, args ...any) error {
+	msg := fmt.Sprintf(format, args...)
+	if strict {
+		return fmt.Errorf("%s (--strict)", msg)
+	}
+	logWarning(msg)
+	return nil
+}
+
+// checkNewTask emits the warnings for a task about to be added
+func checkNewTask(title, description string, dueDate *time.Time, createdAt time.Time) error {
+	var count int
+	err := database.GetDB().QueryRow(`SELECT COUNT(*) FROM tasks WHERE title = ? COLLATE NOCASE`, title).Scan(&count)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		if err := warn("a task titled %q already exists", title); err != nil {
+			return err
+		}
+	}
+
+	if len([]rune(description)) > maxDescriptionLength {
+		if err := warn("description is longer than %d characters", maxDescriptionLength); err != nil {
+			return err
+		}
+	}
+
+	if dueDate != nil && dueDate.Before(createdAt) {
+		if err := warn("due date %s is before the creation time", dueDate.Local().Format("2006-01-02 15:04")); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
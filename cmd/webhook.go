@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eduardamirelly/tasker/config"
+	"github.com/eduardamirelly/tasker/models"
+	"github.com/eduardamirelly/tasker/webhook"
+)
+
+// webhookEnv names the environment variable holding the webhook URL. It
+// takes precedence over the "webhook" setting of the config file.
+const webhookEnv = "TASKER_WEBHOOK"
+
+// webhookClient posts task events during the current command; nil when no
+// webhook is configured
+var webhookClient *webhook.Client
+
+// setupWebhook creates the webhook client when a URL is configured. An
+// invalid URL is reported as a warning and leaves the webhook off, so it never
+// keeps a command from running.
+func setupWebhook() error {
+	webhookClient = nil
+
+	url := os.Getenv(webhookEnv)
+	if url == "" {
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+		url = cfg.Webhook
+	}
+	if url == "" {
+		return nil
+	}
+
+	client, err := webhook.New(url)
+	if err != nil {
+		logWarning("webhook disabled: " + err.Error())
+		return nil
+	}
+	webhookClient = client
+	return nil
+}
+
+// notify sends a task event to the webhook, if one is configured
+func notify(eventType string, task models.Task, changes []fieldChange) {
+	if webhookClient == nil {
+		return
+	}
+
+	event := webhook.Event{Type: eventType, Time: time.Now().UTC(), Task: task}
+	for _, change := range changes {
+		event.Changes = append(event.Changes, webhook.Change{
+			Field:  strings.ToLower(change.Field),
+			Before: change.Before,
+			After:  change.After,
+		})
+	}
+	webhookClient.Send(event)
+}
+
+// notifyAdded sends the task.added event for a newly added task, loading it
+// only when a webhook is configured
+func notifyAdded(id int) {
+	notifyStored(webhook.TaskAdded, id)
+}
+
+// notifyStored sends an event for the task as it is now stored, loading it
+// only when a webhook is configured
+func notifyStored(eventType string, id int) {
+	if webhookClient == nil {
+		return
+	}
+
+	task, err := findTaskById(strconv.Itoa(id))
+	if err != nil {
+		logWarning("webhook not notified: " + err.Error())
+		return
+	}
+	if task == nil {
+		return
+	}
+	notify(eventType, *task, nil)
+}
+
+// flushWebhook waits for the pending webhook deliveries and reports the
+// failed ones as warnings, since the changes themselves were saved
+func flushWebhook() {
+	if webhookClient == nil {
+		return
+	}
+	for _, err := range webhookClient.Wait() {
+		logWarning("webhook delivery failed: " + err.Error())
+	}
+	webhookClient = nil
+}
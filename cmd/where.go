@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/eduardamirelly/tasker/config"
+	"github.com/eduardamirelly/tasker/database"
+	"github.com/spf13/cobra"
+)
+
+var whereCmd = &cobra.Command{
+	Use:   "where",
+	Short: "Show where the tasks are stored",
+	Long: `Print the database file tasker uses, why that one was chosen, and whether
+it exists yet and how large it is.
+
+The database is, in order of precedence:
+  --db                   given on the command line
+  $` + dbPathEnv + `              the environment variable
+  "database"             set in the config file
+  tasker.db              in the current working directory
+
+The database is not opened or created by this command.
+
+Examples:
+  tasker where
+  tasker where --db ~/tasks/tasker.db`,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{noDatabaseAnnotation: "true"},
+	Run: func(cmd *cobra.Command, args []string) {
+		path, source, err := resolveDatabasePath()
+		if err != nil {
+			fmt.Printf("Error resolving the database path: %v\n", err)
+			return
+		}
+
+		fmt.Printf("Database: %s\n", path)
+		fmt.Printf("Source:   %s\n", source)
+
+		info, err := os.Stat(path)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			fmt.Println("Exists:   no, it is created by the first command that uses it")
+		case err != nil:
+			fmt.Printf("Error checking the database file: %v\n", err)
+		default:
+			fmt.Println("Exists:   yes")
+			fmt.Printf("Size:     %d bytes\n", info.Size())
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(whereCmd)
+}
+
+// dbPathEnv names the environment variable holding the database file
+const dbPathEnv = "TASKER_DB"
+
+// noDatabaseAnnotation marks commands that run without opening the database
+const noDatabaseAnnotation = "tasker_no_database"
+
+// dbPath is the database file given with --db
+var dbPath string
+
+// resolveDatabasePath returns the absolute path of the database file to use
+// and where it was configured: --db, then $TASKER_DB, then the config file,
+// then tasker.db in the working directory
+func resolveDatabasePath() (string, string, error) {
+	path, source := dbPath, "--db"
+	if path == "" {
+		path, source = os.Getenv(dbPathEnv), "$"+dbPathEnv
+	}
+	if path == "" {
+		cfg, err := config.Load()
+		if err != nil {
+			return "", "", err
+		}
+		path, source = cfg.Database, "config file"
+	}
+	if path == "" {
+		path, err := database.DefaultFilePath()
+		return path, "default (working directory)", err
+	}
+
+	path, err := filepath.Abs(path)
+	return path, source, err
+}
+
+// openDatabase opens the configured database, unless a connection is already
+// active (as in tests, or after init)
+func openDatabase() error {
+	if database.GetDB() != nil {
+		return nil
+	}
+
+	path, _, err := resolveDatabasePath()
+	if err != nil {
+		return err
+	}
+	database.SetFilePath(path)
+	return database.InitDB()
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// PathEnv names the environment variable overriding the config file location
+const PathEnv = "TASKER_CONFIG"
+
+// Config is the user configuration stored as JSON, e.g.
+//
+//	{
+//	  "defaults": {
+//	    "list": {"sort": "title", "reverse": "true", "overdue": "true"}
+//	  },
+//	  "aliases": {
+//	    "myday": "list --due-today --sort due"
+//	  },
+//	  "tag_icons": {"work": "💼", "home": "🏠"},
+//	  "webhook": "https://example.com/tasker-events"
+//	}
+type Config struct {
+	// Defaults maps a command name to default values for its flags, keyed by
+	// flag name. They apply only when the flag isn't given on the command line.
+	Defaults map[string]map[string]string `json:"defaults,omitempty"`
+	// Aliases maps a saved query name to the command line it stands for
+	Aliases map[string]string `json:"aliases,omitempty"`
+	// TagIcons maps a tag to the emoji or symbol list prefixes its tasks with
+	TagIcons map[string]string `json:"tag_icons,omitempty"`
+	// Webhook is the URL task events are posted to, if any
+	Webhook string `json:"webhook,omitempty"`
+	// Database is the database file, overriding tasker.db in the working directory
+	Database string `json:"database,omitempty"`
+}
+
+// Path returns the config file location: $TASKER_CONFIG when set, otherwise
+// tasker/config.json inside the user's config directory
+func Path() (string, error) {
+	if path := os.Getenv(PathEnv); path != "" {
+		return path, nil
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "tasker", "config.json"), nil
+}
+
+// Load reads the config file. A missing file yields an empty Config.
+func Load() (Config, error) {
+	var cfg Config
+
+	path, err := Path()
+	if err != nil {
+		return cfg, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("failed to read config %s: %w", path, err)
+	}
+
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Save writes cfg to the config file, creating its directory if needed
+func Save(cfg Config) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write config %s: %w", path, err)
+	}
+	return nil
+}
+
+// CommandDefaults returns the flag defaults configured for a command
+func (c Config) CommandDefaults(command string) map[string]string {
+	return c.Defaults[command]
+}
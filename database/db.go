@@ -2,57 +2,246 @@ package database
 
 import (
 	"database/sql"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 
 	_ "github.com/mattn/go-sqlite3" // SQLite driver
 )
 
-var DB *sql.DB
+var (
+	mu sync.RWMutex
+	db *sql.DB
 
-// InitDB initializes the SQLite database
-func InitDB() error {
-	// Get current working directory (project root)
+	// initMu serializes InitDB so concurrent calls open a single connection
+	initMu sync.Mutex
+
+	// filePath is the database file InitDB opens; empty means DefaultFilePath
+	filePath string
+)
+
+// DefaultFilePath returns the database file used unless another one is set:
+// tasker.db in the current working directory
+func DefaultFilePath() (string, error) {
 	currentDir, err := os.Getwd()
 	if err != nil {
-		return err
+		return "", err
 	}
+	return filepath.Join(currentDir, "tasker.db"), nil
+}
+
+// SetFilePath sets the database file the next InitDB opens. An empty path
+// restores DefaultFilePath.
+func SetFilePath(path string) {
+	initMu.Lock()
+	defer initMu.Unlock()
+	filePath = path
+}
+
+// GetDB returns the active database connection
+func GetDB() *sql.DB {
+	mu.RLock()
+	defer mu.RUnlock()
+	return db
+}
 
-	// Create database file path in project directory
-	dbPath := filepath.Join(currentDir, "tasker.db")
+// SetDB replaces the active database connection and returns the previous one,
+// so callers (such as tests) can swap in their own database and restore it later
+func SetDB(newDB *sql.DB) *sql.DB {
+	mu.Lock()
+	defer mu.Unlock()
+	previous := db
+	db = newDB
+	return previous
+}
+
+// InitDB initializes the SQLite database. It is safe to call repeatedly: once
+// a connection is active it is reused. Call CloseDB first to force a new one.
+func InitDB() error {
+	initMu.Lock()
+	defer initMu.Unlock()
+
+	if GetDB() != nil {
+		return nil
+	}
+
+	dbPath := filePath
+	if dbPath == "" {
+		var err error
+		if dbPath, err = DefaultFilePath(); err != nil {
+			return err
+		}
+	}
 
 	// Open database connection
-	db, err := sql.Open("sqlite3", dbPath)
+	conn, err := sql.Open("sqlite3", dbPath)
 	if err != nil {
 		return err
 	}
 
-	DB = db
+	SetDB(conn)
 
-	// Create tasks table if it doesn't exist
-	return createTables()
+	// Create tasks table if it doesn't exist and bring it up to date
+	if err := Migrate(); err != nil {
+		// A read-only database that is out of date can't be upgraded
+		err = WriteError(err)
+		SetDB(nil)
+		conn.Close()
+		return err
+	}
+	return nil
 }
 
-// createTables creates the necessary database tables
-func createTables() error {
-	query := `
+// columnMigrations lists the columns added to the tasks table after its first
+// version, so databases created by older releases are upgraded in place
+var columnMigrations = []struct {
+	name       string
+	definition string
+	// backfill optionally initializes the new column for existing rows
+	backfill string
+}{
+	// Very early databases lack the timestamps. SQLite can't add a column with
+	// a CURRENT_TIMESTAMP default, so existing rows are stamped with the
+	// migration time instead.
+	{"created_at", "DATETIME", "UPDATE tasks SET created_at = CURRENT_TIMESTAMP WHERE created_at IS NULL"},
+	{"completed_at", "DATETIME", ""},
+	{"completion_note", "TEXT", ""},
+	{"due_date", "DATETIME", ""},
+	{"updated_at", "DATETIME", "UPDATE tasks SET updated_at = COALESCE(completed_at, created_at)"},
+	{"uuid", "TEXT", backfillUUIDs},
+	{"priority", "TEXT DEFAULT 'medium'", ""},
+}
+
+// backfillUUIDs gives every task without a uuid a random version 4 UUID
+const backfillUUIDs = `UPDATE tasks SET uuid = lower(
+	hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' || substr(hex(randomblob(2)), 2) || '-' ||
+	substr('89ab', 1 + (abs(random()) % 4), 1) || substr(hex(randomblob(2)), 2) || '-' || hex(randomblob(6))
+) WHERE uuid IS NULL`
+
+// Migrate creates the database tables if needed and adds any missing columns
+func Migrate() error {
+	if err := createTables(); err != nil {
+		return err
+	}
+
+	existing, err := tableColumns("tasks")
+	if err != nil {
+		return err
+	}
+
+	for _, column := range columnMigrations {
+		if existing[column.name] {
+			continue
+		}
+		query := fmt.Sprintf("ALTER TABLE tasks ADD COLUMN %s %s", column.name, column.definition)
+		if _, err := GetDB().Exec(query); err != nil {
+			return fmt.Errorf("failed to add column %s: %w", column.name, err)
+		}
+		if column.backfill != "" {
+			if _, err := GetDB().Exec(column.backfill); err != nil {
+				return fmt.Errorf("failed to backfill column %s: %w", column.name, err)
+			}
+		}
+	}
+
+	_, err = GetDB().Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_tasks_uuid ON tasks(uuid)`)
+	return err
+}
+
+// tableColumns returns the set of column names of a table
+func tableColumns(table string) (map[string]bool, error) {
+	rows, err := GetDB().Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns := make(map[string]bool)
+	for rows.Next() {
+		var (
+			cid          int
+			name, ctype  string
+			notNull, pk  int
+			defaultValue sql.NullString
+		)
+		if err := rows.Scan(&cid, &name, &ctype, &notNull, &defaultValue, &pk); err != nil {
+			return nil, err
+		}
+		columns[name] = true
+	}
+	return columns, rows.Err()
+}
+
+// Schema is the DDL creating the tasker tables. It is also written at the top
+// of SQL exports so they can rebuild the database elsewhere.
+const Schema = `
 	CREATE TABLE IF NOT EXISTS tasks (
 		id INTEGER PRIMARY KEY AUTOINCREMENT,
 		title TEXT NOT NULL,
 		description TEXT,
 		done BOOLEAN DEFAULT FALSE,
 		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-		completed_at DATETIME
+		completed_at DATETIME,
+		completion_note TEXT,
+		due_date DATETIME,
+		updated_at DATETIME,
+		uuid TEXT,
+		priority TEXT DEFAULT 'medium'
+	);
+
+	CREATE TABLE IF NOT EXISTS task_tags (
+		task_id INTEGER NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+		tag TEXT NOT NULL,
+		PRIMARY KEY (task_id, tag)
+	);
+
+	CREATE TABLE IF NOT EXISTS task_attachments (
+		task_id INTEGER NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+		label TEXT NOT NULL DEFAULT '',
+		uri TEXT NOT NULL,
+		PRIMARY KEY (task_id, uri)
+	);
+
+	CREATE TABLE IF NOT EXISTS app_state (
+		key TEXT PRIMARY KEY,
+		value TEXT NOT NULL
 	);`
 
-	_, err := DB.Exec(query)
+// createTables creates the necessary database tables
+func createTables() error {
+	_, err := GetDB().Exec(Schema)
 	return err
 }
 
-// CloseDB closes the database connection
+// Path returns the file of the active database as reported by SQLite, or an
+// empty string for in-memory databases
+func Path() (string, error) {
+	rows, err := GetDB().Query("PRAGMA database_list")
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			seq        int
+			name, file string
+		)
+		if err := rows.Scan(&seq, &name, &file); err != nil {
+			return "", err
+		}
+		if name == "main" {
+			return file, nil
+		}
+	}
+	return "", rows.Err()
+}
+
+// CloseDB closes the database connection. The next InitDB opens a new one.
 func CloseDB() error {
-	if DB != nil {
-		return DB.Close()
+	if conn := SetDB(nil); conn != nil {
+		return conn.Close()
 	}
 	return nil
 }
@@ -0,0 +1,44 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// ReadOnlyError reports a change refused because the database file, or the
+// filesystem holding it, is read-only
+type ReadOnlyError struct {
+	// Path is the database file, empty when it isn't known
+	Path string
+}
+
+func (e *ReadOnlyError) Error() string {
+	if e.Path == "" {
+		return "the database is read-only, changes can't be saved"
+	}
+	return fmt.Sprintf("the database %s is read-only, changes can't be saved (check the permissions of the file and its directory)", e.Path)
+}
+
+// WriteError replaces the SQLite error of a refused write to a read-only
+// database with a *ReadOnlyError naming the file. Other errors, including nil,
+// are returned unchanged.
+func WriteError(err error) error {
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) || sqliteErr.Code != sqlite3.ErrReadonly {
+		return err
+	}
+
+	path, pathErr := Path()
+	if pathErr != nil {
+		path = ""
+	}
+	return &ReadOnlyError{Path: path}
+}
+
+// IsReadOnly reports whether err is a refused write to a read-only database
+func IsReadOnly(err error) bool {
+	var readOnly *ReadOnlyError
+	return errors.As(err, &readOnly)
+}
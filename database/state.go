@@ -0,0 +1,28 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+)
+
+// GetState returns the value stored under key in the app_state table, and
+// whether there was one
+func GetState(key string) (string, bool, error) {
+	var value string
+	err := GetDB().QueryRow(`SELECT value FROM app_state WHERE key = ?`, key).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return value, true, nil
+}
+
+// SetState stores value under key in the app_state table, replacing any
+// previous value
+func SetState(key, value string) error {
+	_, err := GetDB().Exec(`INSERT INTO app_state (key, value) VALUES (?, ?)
+		ON CONFLICT(key) DO UPDATE SET value = excluded.value`, key, value)
+	return WriteError(err)
+}
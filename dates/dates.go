@@ -0,0 +1,58 @@
+package dates
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// WeekStartEnv names the environment variable overriding the first day of the week
+const WeekStartEnv = "TASKER_WEEK_START"
+
+// DayBounds returns the start of the day containing t and the start of the next day,
+// in t's location
+func DayBounds(t time.Time) (time.Time, time.Time) {
+	start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return start, start.AddDate(0, 0, 1)
+}
+
+// WeekBounds returns the start of the week containing t and the start of the
+// following week, for weeks beginning on weekStart
+func WeekBounds(t time.Time, weekStart time.Weekday) (time.Time, time.Time) {
+	dayStart, _ := DayBounds(t)
+	offset := (int(t.Weekday()) - int(weekStart) + 7) % 7
+	start := dayStart.AddDate(0, 0, -offset)
+	return start, start.AddDate(0, 0, 7)
+}
+
+// MonthBounds returns the start of the calendar month containing t and the
+// start of the next month, in t's location
+func MonthBounds(t time.Time) (time.Time, time.Time) {
+	start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	// Adding a month to the first of a month never overflows into the next one
+	return start, start.AddDate(0, 1, 0)
+}
+
+// ParseMonth parses a calendar month written "2006-01" and returns its
+// bounds as MonthBounds does, in loc
+func ParseMonth(value string, loc *time.Location) (time.Time, time.Time, error) {
+	month, err := time.ParseInLocation("2006-01", strings.TrimSpace(value), loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid month %q, expected YYYY-MM", value)
+	}
+	start, end := MonthBounds(month)
+	return start, end, nil
+}
+
+// WeekStart returns the configured first day of the week. It reads
+// TASKER_WEEK_START (a weekday name such as "sunday") and defaults to Monday.
+func WeekStart() time.Weekday {
+	value := strings.ToLower(strings.TrimSpace(os.Getenv(WeekStartEnv)))
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if value == strings.ToLower(day.String()) {
+			return day
+		}
+	}
+	return time.Monday
+}
@@ -0,0 +1,210 @@
+package dates
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	inPattern   = regexp.MustCompile(`^in (\d+) (minute|hour|day|week|month)s?$`)
+	agoPattern  = regexp.MustCompile(`^(\d+)(d|w)$`)
+	timePattern = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?\s*(am|pm)?$`)
+)
+
+// ParseDue resolves a due date relative to now. It accepts:
+//
+//   - absolute dates: "2006-01-02" or "2006-01-02 15:04"
+//   - "today", "tomorrow", a weekday ("friday") or "next <weekday>"
+//   - "in N minutes/hours/days/weeks/months"
+//
+// Day expressions may be followed by a time such as "5pm", "5:30pm" or
+// "17:00"; without one they mean the end of that day.
+func ParseDue(value string, now time.Time) (time.Time, error) {
+	input := strings.ToLower(strings.Join(strings.Fields(value), " "))
+	if input == "" {
+		return time.Time{}, fmt.Errorf("empty due date")
+	}
+
+	if t, err := time.ParseInLocation("2006-01-02 15:04", input, now.Location()); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", input, now.Location()); err == nil {
+		return endOfDay(t), nil
+	}
+
+	if match := inPattern.FindStringSubmatch(input); match != nil {
+		n, _ := strconv.Atoi(match[1])
+		switch match[2] {
+		case "minute":
+			return now.Add(time.Duration(n) * time.Minute), nil
+		case "hour":
+			return now.Add(time.Duration(n) * time.Hour), nil
+		case "day":
+			return endOfDay(now.AddDate(0, 0, n)), nil
+		case "week":
+			return endOfDay(now.AddDate(0, 0, 7*n)), nil
+		case "month":
+			return endOfDay(now.AddDate(0, n, 0)), nil
+		}
+	}
+
+	day, rest, ok := parseDay(input, now)
+	if !ok {
+		return time.Time{}, fmt.Errorf(`invalid due date %q, expected YYYY-MM-DD, "YYYY-MM-DD HH:MM" or e.g. "tomorrow 5pm", "next monday", "in 3 days"`, value)
+	}
+	if rest == "" {
+		return endOfDay(day), nil
+	}
+
+	hour, minute, err := parseClock(rest)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time in due date %q: %w", value, err)
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, day.Location()), nil
+}
+
+// parseDay parses the leading day expression of input and returns the day,
+// the remaining text and whether a day expression was found
+func parseDay(input string, now time.Time) (time.Time, string, bool) {
+	words := strings.SplitN(input, " ", 3)
+
+	switch words[0] {
+	case "today":
+		return now, strings.Join(words[1:], " "), true
+	case "tomorrow":
+		return now.AddDate(0, 0, 1), strings.Join(words[1:], " "), true
+	case "next":
+		if len(words) > 1 {
+			if weekday, ok := parseWeekday(words[1]); ok {
+				return nextWeekday(now, weekday), strings.Join(words[2:], " "), true
+			}
+		}
+	default:
+		if weekday, ok := parseWeekday(words[0]); ok {
+			return nextWeekday(now, weekday), strings.Join(words[1:], " "), true
+		}
+	}
+	return time.Time{}, "", false
+}
+
+// parseClock parses a time of day such as "5pm", "5:30 pm" or "17:00"
+func parseClock(value string) (int, int, error) {
+	match := timePattern.FindStringSubmatch(value)
+	if match == nil {
+		return 0, 0, fmt.Errorf("unrecognized time %q", value)
+	}
+
+	hour, _ := strconv.Atoi(match[1])
+	minute := 0
+	if match[2] != "" {
+		minute, _ = strconv.Atoi(match[2])
+	}
+
+	switch match[3] {
+	case "am", "pm":
+		if hour < 1 || hour > 12 {
+			return 0, 0, fmt.Errorf("hour out of range in %q", value)
+		}
+		hour %= 12
+		if match[3] == "pm" {
+			hour += 12
+		}
+	default:
+		if hour > 23 {
+			return 0, 0, fmt.Errorf("hour out of range in %q", value)
+		}
+	}
+	if minute > 59 {
+		return 0, 0, fmt.Errorf("minute out of range in %q", value)
+	}
+	return hour, minute, nil
+}
+
+// parseWeekday parses a full or three-letter weekday name
+func parseWeekday(value string) (time.Weekday, bool) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		name := strings.ToLower(day.String())
+		if value == name || value == name[:3] {
+			return day, true
+		}
+	}
+	return 0, false
+}
+
+// nextWeekday returns the next day after now falling on weekday (never today)
+func nextWeekday(now time.Time, weekday time.Weekday) time.Time {
+	days := (int(weekday) - int(now.Weekday()) + 7) % 7
+	if days == 0 {
+		days = 7
+	}
+	return now.AddDate(0, 0, days)
+}
+
+// endOfDay returns the last second of the day containing t
+func endOfDay(t time.Time) time.Time {
+	_, nextDay := DayBounds(t)
+	return nextDay.Add(-time.Second)
+}
+
+// ParseSince resolves the start of a "since" window. It accepts an absolute
+// date ("2006-01-02", meaning the start of that day), a date and time
+// ("2006-01-02 15:04" or RFC 3339), "today" or "yesterday" (their start), a
+// duration before now such as "24h" or "90m", or a number of days or weeks
+// before now such as "30d" or "2w".
+func ParseSince(value string, now time.Time) (time.Time, error) {
+	t, _, err := parseBound(value, now)
+	return t, err
+}
+
+// ParseUntil resolves the end of a window, excluded from it. It accepts the
+// same values as ParseSince, except that a date alone, "today" and
+// "yesterday" mean the end of that day, so the whole day is included.
+func ParseUntil(value string, now time.Time) (time.Time, error) {
+	t, wholeDay, err := parseBound(value, now)
+	if err != nil || !wholeDay {
+		return t, err
+	}
+	_, end := DayBounds(t)
+	return end, nil
+}
+
+// parseBound parses a ParseSince value, reporting whether it named a whole
+// day, in which case the start of that day is returned
+func parseBound(value string, now time.Time) (time.Time, bool, error) {
+	input := strings.ToLower(strings.TrimSpace(value))
+
+	if t, err := time.Parse(time.RFC3339, input); err == nil {
+		return t, false, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02 15:04", input, now.Location()); err == nil {
+		return t, false, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", input, now.Location()); err == nil {
+		return t, true, nil
+	}
+
+	switch input {
+	case "today":
+		start, _ := DayBounds(now)
+		return start, true, nil
+	case "yesterday":
+		start, _ := DayBounds(now.AddDate(0, 0, -1))
+		return start, true, nil
+	}
+
+	if match := agoPattern.FindStringSubmatch(input); match != nil {
+		n, _ := strconv.Atoi(match[1])
+		if match[2] == "w" {
+			n *= 7
+		}
+		return now.AddDate(0, 0, -n), false, nil
+	}
+	if d, err := time.ParseDuration(input); err == nil && d >= 0 {
+		return now.Add(-d), false, nil
+	}
+
+	return time.Time{}, false, fmt.Errorf(`invalid time %q, expected YYYY-MM-DD, "YYYY-MM-DD HH:MM", today, yesterday or a time ago such as 24h or 30d`, value)
+}
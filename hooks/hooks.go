@@ -0,0 +1,141 @@
+package hooks
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"text/template"
+)
+
+// Runner executes a program with its arguments
+type Runner func(name string, args ...string) error
+
+// ExecRunner runs the program directly, without a shell, forwarding its output
+func ExecRunner(name string, args ...string) error {
+	command := exec.Command(name, args...)
+	command.Stdout = os.Stdout
+	command.Stderr = os.Stderr
+	return command.Run()
+}
+
+// Command is a command line template such as `notify-send "Overdue" {{.Title}}`.
+//
+// The line is split into words before any template is rendered, and the
+// program is run without a shell, so rendered values always stay a single
+// argument: a task title containing spaces, quotes or `;` can't inject extra
+// arguments or commands.
+type Command struct {
+	words []*template.Template
+}
+
+// Parse splits a command line into words and parses each one as a template
+func Parse(line string, funcs template.FuncMap) (*Command, error) {
+	words, err := splitWords(line)
+	if err != nil {
+		return nil, err
+	}
+	if len(words) == 0 {
+		return nil, fmt.Errorf("empty command")
+	}
+
+	command := &Command{}
+	for i, word := range words {
+		tmpl, err := template.New(fmt.Sprintf("word%d", i)).Funcs(funcs).Parse(word)
+		if err != nil {
+			return nil, err
+		}
+		command.words = append(command.words, tmpl)
+	}
+	return command, nil
+}
+
+// Render renders every word of the command with data
+func (c *Command) Render(data interface{}) ([]string, error) {
+	args := make([]string, 0, len(c.words))
+	for _, word := range c.words {
+		var rendered strings.Builder
+		if err := word.Execute(&rendered, data); err != nil {
+			return nil, err
+		}
+		args = append(args, rendered.String())
+	}
+	return args, nil
+}
+
+// Run renders the command with data and executes it with runner
+func (c *Command) Run(runner Runner, data interface{}) error {
+	args, err := c.Render(data)
+	if err != nil {
+		return err
+	}
+	return runner(args[0], args[1:]...)
+}
+
+// splitWords splits a command line on whitespace like a shell would, honoring
+// single and double quotes and backslash escapes. Template actions ({{ ... }})
+// are kept verbatim so they may contain spaces and quoted arguments.
+func splitWords(line string) ([]string, error) {
+	var (
+		words   []string
+		current strings.Builder
+		inWord  bool
+		quote   rune
+	)
+
+	runes := []rune(line)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		// Copy template actions untouched
+		if quote != '\'' && r == '{' && i+1 < len(runes) && runes[i+1] == '{' {
+			end := indexActionEnd(runes, i+2)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated template action in %q", line)
+			}
+			current.WriteString(string(runes[i : end+2]))
+			i = end + 1
+			inWord = true
+			continue
+		}
+
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote == 0 && (r == '\'' || r == '"'):
+			quote = r
+			inWord = true
+		case quote != '\'' && r == '\\' && i+1 < len(runes):
+			i++
+			current.WriteRune(runes[i])
+			inWord = true
+		case quote == 0 && (r == ' ' || r == '\t' || r == '\n'):
+			if inWord {
+				words = append(words, current.String())
+				current.Reset()
+				inWord = false
+			}
+		default:
+			current.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote in %q", line)
+	}
+	if inWord {
+		words = append(words, current.String())
+	}
+	return words, nil
+}
+
+// indexActionEnd returns the index of the "}}" closing a template action, searching from start
+func indexActionEnd(runes []rune, start int) int {
+	for i := start; i+1 < len(runes); i++ {
+		if runes[i] == '}' && runes[i+1] == '}' {
+			return i
+		}
+	}
+	return -1
+}
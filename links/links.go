@@ -0,0 +1,55 @@
+package links
+
+import (
+	"errors"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// ErrNoURL is returned when none of the searched texts contains a URL
+var ErrNoURL = errors.New("no URL found")
+
+var urlPattern = regexp.MustCompile(`https?://[^\s<>"']+`)
+
+// Opener opens a URL, typically in the default browser
+type Opener func(url string) error
+
+// SystemOpener opens url with the platform's default handler (xdg-open on
+// Linux/BSD, open on macOS, the URL protocol handler on Windows). None of them
+// goes through a shell, so characters such as & in the URL are never run as
+// commands.
+func SystemOpener(url string) error {
+	var command *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		command = exec.Command("open", url)
+	case "windows":
+		command = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		command = exec.Command("xdg-open", url)
+	}
+	return command.Start()
+}
+
+// FirstURL returns the first http(s) URL found in texts, searched in order,
+// or an empty string when there is none. Trailing punctuation that usually
+// ends a sentence rather than the URL is dropped.
+func FirstURL(texts ...string) string {
+	for _, text := range texts {
+		if match := urlPattern.FindString(text); match != "" {
+			return strings.TrimRight(match, ".,;:!?)]")
+		}
+	}
+	return ""
+}
+
+// OpenFirst opens the first URL found in texts with opener and returns it
+func OpenFirst(opener Opener, texts ...string) (string, error) {
+	url := FirstURL(texts...)
+	if url == "" {
+		return "", ErrNoURL
+	}
+	return url, opener(url)
+}
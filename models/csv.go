@@ -0,0 +1,103 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CSVTimeLayout is the timestamp format used in CSV records
+const CSVTimeLayout = "2006-01-02 15:04:05"
+
+// CSVPreciseTimeLayout keeps sub-second precision so CSV round-trips are lossless
+const CSVPreciseTimeLayout = time.RFC3339Nano
+
+// CSVHeader is the header row matching the columns of a task CSV record
+var CSVHeader = []string{"ID", "Title", "Description", "Done", "Created At", "Completed At", "UUID"}
+
+// csvColumnsWithoutUUID is the column count of CSV files written before the
+// UUID column was added, which are still accepted
+const csvColumnsWithoutUUID = 6
+
+// ToCSVRecord converts the task into a CSV record ordered like CSVHeader.
+// A nil completion time becomes an empty field.
+func (t Task) ToCSVRecord() []string {
+	return t.ToCSVRecordWithLayout(CSVTimeLayout)
+}
+
+// ToCSVRecordWithLayout is like ToCSVRecord but formats timestamps with layout
+func (t Task) ToCSVRecordWithLayout(layout string) []string {
+	completedAt := ""
+	if t.CompletedAt != nil {
+		completedAt = t.CompletedAt.Format(layout)
+	}
+
+	return []string{
+		strconv.Itoa(t.ID),
+		t.Title,
+		t.Description,
+		strconv.FormatBool(t.Done),
+		t.CreatedAt.Format(layout),
+		completedAt,
+		t.UUID,
+	}
+}
+
+// TaskFromCSVRecord builds a task from a CSV record ordered like CSVHeader.
+// Timestamps may use either CSVTimeLayout or CSVPreciseTimeLayout. The UUID
+// column may be missing or empty, leaving the uuid unset.
+func TaskFromCSVRecord(record []string) (Task, error) {
+	var task Task
+
+	if len(record) != len(CSVHeader) && len(record) != csvColumnsWithoutUUID {
+		return task, fmt.Errorf("expected %d or %d columns, got %d", csvColumnsWithoutUUID, len(CSVHeader), len(record))
+	}
+
+	id, err := strconv.Atoi(strings.TrimSpace(record[0]))
+	if err != nil {
+		return task, fmt.Errorf("invalid id %q: %w", record[0], err)
+	}
+
+	done, err := strconv.ParseBool(strings.TrimSpace(record[3]))
+	if err != nil {
+		return task, fmt.Errorf("invalid done value %q: %w", record[3], err)
+	}
+
+	createdAt, err := parseCSVTime(record[4])
+	if err != nil {
+		return task, fmt.Errorf("invalid created at %q: %w", record[4], err)
+	}
+
+	task.ID = id
+	task.Title = record[1]
+	task.Description = record[2]
+	task.Done = done
+	task.CreatedAt = createdAt
+
+	if completed := strings.TrimSpace(record[5]); completed != "" {
+		completedAt, err := parseCSVTime(completed)
+		if err != nil {
+			return task, fmt.Errorf("invalid completed at %q: %w", record[5], err)
+		}
+		task.CompletedAt = &completedAt
+	}
+
+	if len(record) > csvColumnsWithoutUUID {
+		task.UUID = NormalizeUUID(record[6])
+		if task.UUID != "" && !ValidUUID(task.UUID) {
+			return task, fmt.Errorf("invalid uuid %q", record[6])
+		}
+	}
+
+	return task, nil
+}
+
+// parseCSVTime parses a CSV timestamp in CSVTimeLayout or CSVPreciseTimeLayout
+func parseCSVTime(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if t, err := time.Parse(CSVPreciseTimeLayout, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(CSVTimeLayout, value)
+}
@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// DueDisplayLayout is the format used to display due dates
+const DueDisplayLayout = "2006-01-02 15:04"
+
+// IsOverdue reports whether the task is still pending after its due date
+func (t Task) IsOverdue(now time.Time) bool {
+	return !t.Done && t.DueDate != nil && t.DueDate.Before(now)
+}
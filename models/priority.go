@@ -0,0 +1,30 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Task priorities, from least to most urgent
+const (
+	PriorityLow    = "low"
+	PriorityMedium = "medium"
+	PriorityHigh   = "high"
+)
+
+// DefaultPriority is the priority of tasks added without one
+const DefaultPriority = PriorityMedium
+
+// Priorities lists the valid priorities from least to most urgent
+var Priorities = []string{PriorityLow, PriorityMedium, PriorityHigh}
+
+// ParsePriority normalizes a priority name, accepting any letter case
+func ParsePriority(value string) (string, error) {
+	priority := strings.ToLower(strings.TrimSpace(value))
+	for _, valid := range Priorities {
+		if priority == valid {
+			return priority, nil
+		}
+	}
+	return "", fmt.Errorf("invalid priority %q (valid: %s)", value, strings.Join(Priorities, ", "))
+}
@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 // Task represents a single task in our system
 type Task struct {
@@ -10,4 +14,47 @@ type Task struct {
 	Done        bool       `json:"done"`
 	CreatedAt   time.Time  `json:"created_at"`
 	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	// UUID is a stable identifier that, unlike ID, never hints at task counts
+	UUID string `json:"uuid,omitempty"`
+	// CompletionNote optionally records how or why the task was completed
+	CompletionNote *string `json:"completion_note,omitempty"`
+	// DueDate is the optional deadline of the task
+	DueDate *time.Time `json:"due_date,omitempty"`
+	// Tags are the lowercase labels grouping the task, sorted by name
+	Tags []string `json:"tags,omitempty"`
+	// UpdatedAt is when the task was last added, edited or completed
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+	// Priority is one of Priorities
+	Priority string `json:"priority,omitempty"`
+	// Attachments are the files and URLs attached to the task, oldest first.
+	// They are only loaded for a single task (e.g. by show).
+	Attachments []Attachment `json:"attachments,omitempty"`
+}
+
+// Attachment is a file path or URL attached to a task, with an optional label
+type Attachment struct {
+	Label string `json:"label,omitempty"`
+	URI   string `json:"uri"`
+}
+
+// Validate reports the first problem preventing the task from being stored:
+// a blank title, an unknown priority (an empty one means the default) or a
+// completion time before the creation time
+func (t Task) Validate() error {
+	if strings.TrimSpace(t.Title) == "" {
+		return fmt.Errorf("title cannot be empty")
+	}
+	if t.Priority != "" {
+		if _, err := ParsePriority(t.Priority); err != nil {
+			return err
+		}
+	}
+	if t.UUID != "" && !ValidUUID(t.UUID) {
+		return fmt.Errorf("invalid uuid %q", t.UUID)
+	}
+	if t.CompletedAt != nil && !t.CreatedAt.IsZero() && t.CompletedAt.Before(t.CreatedAt) {
+		return fmt.Errorf("completed at %s, before it was created at %s",
+			t.CompletedAt.Format(CSVTimeLayout), t.CreatedAt.Format(CSVTimeLayout))
+	}
+	return nil
 }
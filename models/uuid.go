@@ -0,0 +1,31 @@
+package models
+
+import (
+	"crypto/rand"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// NewUUID returns a random (version 4) UUID in its lowercase canonical form
+func NewUUID() string {
+	var b [16]byte
+	// crypto/rand.Read never returns an error on supported platforms
+	rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// uuidPattern matches a UUID in its canonical form, in either letter case
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// ValidUUID reports whether s is a UUID in its canonical form
+func ValidUUID(s string) bool {
+	return uuidPattern.MatchString(s)
+}
+
+// NormalizeUUID trims s and lowercases it, the form uuids are stored in
+func NormalizeUUID(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
@@ -0,0 +1,65 @@
+package progress
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// Threshold is the number of items an operation must exceed before progress is shown
+const Threshold = 500
+
+// ShouldShow decides whether a progress indicator is worth displaying for an
+// operation over total items. Progress only goes to terminals and is suppressed
+// in quiet mode.
+func ShouldShow(total int, isTTY, quiet bool) bool {
+	return !quiet && isTTY && total > Threshold
+}
+
+// IsTerminal reports whether f is attached to a terminal
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// Reporter writes a line-count and percentage indicator to a writer.
+// A disabled Reporter silently ignores all calls.
+type Reporter struct {
+	w           io.Writer
+	label       string
+	total       int
+	current     int
+	lastPercent int
+	enabled     bool
+}
+
+// New creates a Reporter for an operation over total items
+func New(w io.Writer, label string, total int, enabled bool) *Reporter {
+	return &Reporter{w: w, label: label, total: total, lastPercent: -1, enabled: enabled && total > 0}
+}
+
+// Increment records one processed item, redrawing the indicator whenever the
+// percentage changes
+func (r *Reporter) Increment() {
+	if !r.enabled {
+		return
+	}
+	r.current++
+	percent := r.current * 100 / r.total
+	if percent == r.lastPercent {
+		return
+	}
+	r.lastPercent = percent
+	fmt.Fprintf(r.w, "\r%s %d/%d (%d%%)", r.label, r.current, r.total, percent)
+}
+
+// Done finishes the indicator line
+func (r *Reporter) Done() {
+	if !r.enabled {
+		return
+	}
+	fmt.Fprintln(r.w)
+}
@@ -0,0 +1,60 @@
+package prompt
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrNotInteractive is returned by Confirm when no answer can be read because
+// input is not a terminal; callers should ask for --yes instead
+var ErrNotInteractive = errors.New("input is not a terminal, pass --yes to confirm")
+
+// Confirm asks question on out and reads a yes/no answer from in. Only "y" or
+// "yes" (case-insensitive) confirm; an empty answer, end of input or a read
+// error all mean no. When interactive is false nothing is read and
+// ErrNotInteractive is returned, so piped or scheduled runs never block.
+func Confirm(in io.Reader, out io.Writer, question string, interactive bool) (bool, error) {
+	if !interactive {
+		return false, ErrNotInteractive
+	}
+
+	fmt.Fprintf(out, "%s [y/N]: ", question)
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && answer == "" {
+		fmt.Fprintln(out)
+		return false, nil
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// Ask shows question on out, with defaultValue in brackets when there is one,
+// and returns the trimmed answer read from in, or defaultValue when the answer
+// is empty. io.EOF is returned only when in ends before any answer is given.
+// Reuse the same reader for consecutive questions so no buffered input is lost.
+func Ask(in *bufio.Reader, out io.Writer, question, defaultValue string) (string, error) {
+	if defaultValue != "" {
+		fmt.Fprintf(out, "%s [%s]: ", question, defaultValue)
+	} else {
+		fmt.Fprintf(out, "%s: ", question)
+	}
+
+	answer, err := in.ReadString('\n')
+	if err != nil && answer == "" {
+		fmt.Fprintln(out)
+		return "", err
+	}
+
+	if answer = strings.TrimSpace(answer); answer == "" {
+		return defaultValue, nil
+	}
+	return answer, nil
+}
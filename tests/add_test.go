@@ -1,9 +1,13 @@
 package tests
 
 import (
+	"database/sql"
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/eduardamirelly/tasker/cmd"
 	"github.com/eduardamirelly/tasker/database"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -63,7 +67,7 @@ func TestAddTask(t *testing.T) {
 			// Execute addTask function - we need to access the private function
 			// For now, we'll test via the public database operations
 			query := `INSERT INTO tasks (title, description, created_at) VALUES (?, ?, ?)`
-			_, err := database.DB.Exec(query, tt.title, tt.description, "2023-01-01")
+			_, err := database.GetDB().Exec(query, tt.title, tt.description, "2023-01-01")
 
 			// Check error expectation
 			if tt.wantErr {
@@ -82,7 +86,7 @@ func TestAddTask(t *testing.T) {
 			queryCheck := `SELECT title, description, done FROM tasks WHERE title = ?`
 			var title, description string
 			var done bool
-			err = database.DB.QueryRow(queryCheck, tt.title).Scan(&title, &description, &done)
+			err = database.GetDB().QueryRow(queryCheck, tt.title).Scan(&title, &description, &done)
 			require.NoError(t, err)
 
 			assert.Equal(t, tt.title, title)
@@ -99,7 +103,7 @@ func TestAddTaskEmptyTitle(t *testing.T) {
 
 	// Test with empty title - this should still work as our database allows it
 	query := `INSERT INTO tasks (title, description, created_at) VALUES (?, ?, ?)`
-	_, err := database.DB.Exec(query, "", "Description without title", "2023-01-01")
+	_, err := database.GetDB().Exec(query, "", "Description without title", "2023-01-01")
 	assert.NoError(t, err)
 
 	// Verify the task was added
@@ -124,7 +128,7 @@ func TestAddTaskMultipleTasks(t *testing.T) {
 	// Add multiple tasks
 	for i, task := range tasks {
 		query := `INSERT INTO tasks (title, description, created_at) VALUES (?, ?, ?)`
-		_, err := database.DB.Exec(query, task.title, task.description, "2023-01-01")
+		_, err := database.GetDB().Exec(query, task.title, task.description, "2023-01-01")
 		require.NoError(t, err)
 
 		// Verify count increases correctly
@@ -151,7 +155,7 @@ func TestAddTaskConcurrency(t *testing.T) {
 			title := fmt.Sprintf("Concurrent Task %d", index)
 			description := fmt.Sprintf("Description for task %d", index)
 			query := `INSERT INTO tasks (title, description, created_at) VALUES (?, ?, ?)`
-			_, err := database.DB.Exec(query, title, description, "2023-01-01")
+			_, err := database.GetDB().Exec(query, title, description, "2023-01-01")
 			errChan <- err
 		}(i)
 	}
@@ -166,3 +170,368 @@ func TestAddTaskConcurrency(t *testing.T) {
 	count := getTaskCount(t)
 	assert.Equal(t, taskCount, count)
 }
+
+func TestAddTaskWithDueDate(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	t.Run("date and time", func(t *testing.T) {
+		clearTestTasks(t)
+
+		output := runCommand(t, "add", "Team meeting", "--due", "2024-06-01 14:30")
+		assert.Contains(t, output, "✓ Task added: Team meeting")
+
+		var dueDate time.Time
+		err := database.GetDB().QueryRow(`SELECT due_date FROM tasks WHERE title = ?`, "Team meeting").Scan(&dueDate)
+		require.NoError(t, err)
+		assert.True(t, time.Date(2024, 6, 1, 14, 30, 0, 0, time.Local).Equal(dueDate))
+	})
+
+	t.Run("natural language", func(t *testing.T) {
+		clearTestTasks(t)
+
+		output := runCommand(t, "add", "Call the bank", "--due", "tomorrow 5pm")
+		assert.Contains(t, output, "✓ Task added: Call the bank")
+
+		var dueDate time.Time
+		err := database.GetDB().QueryRow(`SELECT due_date FROM tasks WHERE title = ?`, "Call the bank").Scan(&dueDate)
+		require.NoError(t, err)
+		tomorrow := time.Now().AddDate(0, 0, 1)
+		expected := time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 17, 0, 0, 0, time.Local)
+		assert.True(t, expected.Equal(dueDate), "expected %v, got %v", expected, dueDate)
+	})
+
+	t.Run("invalid due date is rejected", func(t *testing.T) {
+		clearTestTasks(t)
+
+		output := runCommand(t, "add", "Team meeting", "--due", "someday")
+		assert.Contains(t, output, "invalid due date")
+		assert.Equal(t, 0, getTaskCount(t))
+	})
+}
+
+func TestAddTaskStrict(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	t.Run("duplicate title warns by default", func(t *testing.T) {
+		clearTestTasks(t)
+
+		runCommand(t, "add", "Buy groceries")
+		output := runCommand(t, "add", "buy groceries")
+		assert.Contains(t, output, `⚠️  Warning: a task titled "buy groceries" already exists`)
+		assert.Contains(t, output, "✓ Task added: buy groceries")
+		assert.Equal(t, 2, getTaskCount(t))
+	})
+
+	t.Run("duplicate title fails under --strict", func(t *testing.T) {
+		clearTestTasks(t)
+
+		runCommand(t, "add", "Buy groceries")
+		output, err := runCommandErr(t, "add", "Buy groceries", "--strict")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "already exists")
+		assert.NotContains(t, output, "✓ Task added")
+		assert.Equal(t, 1, getTaskCount(t))
+	})
+
+	t.Run("long description and past due date", func(t *testing.T) {
+		clearTestTasks(t)
+
+		output := runCommand(t, "add", "Write report", "-d", strings.Repeat("x", 501), "--due", "2000-01-01")
+		assert.Contains(t, output, "description is longer than 500 characters")
+		assert.Contains(t, output, "due date 2000-01-01 23:59 is before the creation time")
+
+		_, err := runCommandErr(t, "add", "Write summary", "--due", "2000-01-01", "--strict")
+		require.Error(t, err)
+		assert.Equal(t, 1, getTaskCount(t))
+	})
+}
+
+func TestAddTaskUUID(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+	runCommand(t, "add", "First task")
+	runCommand(t, "add", "Second task")
+
+	uuids := map[string]string{}
+	rows, err := database.GetDB().Query(`SELECT uuid, title FROM tasks`)
+	require.NoError(t, err)
+	for rows.Next() {
+		var uuid, title string
+		require.NoError(t, rows.Scan(&uuid, &title))
+		assert.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, uuid)
+		uuids[uuid] = title
+	}
+	require.NoError(t, rows.Err())
+	rows.Close()
+	require.Len(t, uuids, 2, "uuids must be unique")
+
+	for uuid, title := range uuids {
+		output := runCommand(t, "show", uuid)
+		assert.Contains(t, output, "Title: "+title)
+		assert.Contains(t, output, "UUID: "+uuid)
+
+		output = runCommand(t, "show", strings.ToUpper(uuid))
+		assert.Contains(t, output, "Title: "+title)
+	}
+
+	for uuid, title := range uuids {
+		if title == "Second task" {
+			output := runCommand(t, "done", uuid)
+			assert.Contains(t, output, "✓ Task marked as done: Second task")
+		}
+	}
+	var doneTitle string
+	require.NoError(t, database.GetDB().QueryRow(`SELECT title FROM tasks WHERE done = TRUE`).Scan(&doneTitle))
+	assert.Equal(t, "Second task", doneTitle)
+
+	assert.Contains(t, runCommand(t, "show", "00000000-0000-4000-8000-000000000000"), "❌ Task not found")
+}
+
+func TestAddTaskPriority(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+	runCommand(t, "add", "Fix outage", "--priority", "HIGH")
+	runCommand(t, "add", "Water plants")
+
+	output := runCommand(t, "add", "Someday", "--priority", "urgent")
+	assert.Contains(t, output, `Error adding task: invalid priority "urgent"`)
+
+	priorities := map[string]string{}
+	rows, err := database.GetDB().Query(`SELECT title, priority FROM tasks`)
+	require.NoError(t, err)
+	for rows.Next() {
+		var title, priority string
+		require.NoError(t, rows.Scan(&title, &priority))
+		priorities[title] = priority
+	}
+	require.NoError(t, rows.Err())
+	rows.Close()
+
+	assert.Equal(t, map[string]string{"Fix outage": "high", "Water plants": "medium"}, priorities)
+}
+
+func TestAddTaskDefaultPriority(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	priorityOf := func(t *testing.T, title string) string {
+		var priority string
+		require.NoError(t, database.GetDB().QueryRow(`SELECT priority FROM tasks WHERE title = ?`, title).Scan(&priority))
+		return priority
+	}
+
+	t.Run("medium without configuration", func(t *testing.T) {
+		clearTestTasks(t)
+		runCommand(t, "add", "Water plants")
+		assert.Equal(t, "medium", priorityOf(t, "Water plants"))
+	})
+
+	t.Run("environment sets the default", func(t *testing.T) {
+		clearTestTasks(t)
+		t.Setenv("TASKER_DEFAULT_PRIORITY", "High")
+		runCommand(t, "add", "Fix outage")
+		assert.Equal(t, "high", priorityOf(t, "Fix outage"))
+
+		// An explicit --priority still wins
+		runCommand(t, "add", "Someday", "--priority", "low")
+		assert.Equal(t, "low", priorityOf(t, "Someday"))
+	})
+
+	t.Run("config file sets the default", func(t *testing.T) {
+		clearTestTasks(t)
+		writeTestConfig(t, `{"defaults": {"add": {"priority": "low"}}}`)
+		defer writeTestConfig(t, `{}`)
+		runCommand(t, "add", "Read a book")
+		assert.Equal(t, "low", priorityOf(t, "Read a book"))
+
+		// The environment takes precedence over the config file
+		t.Setenv("TASKER_DEFAULT_PRIORITY", "high")
+		runCommand(t, "add", "Call the bank")
+		assert.Equal(t, "high", priorityOf(t, "Call the bank"))
+	})
+
+	t.Run("interactive prompt offers the default", func(t *testing.T) {
+		clearTestTasks(t)
+		t.Setenv("TASKER_DEFAULT_PRIORITY", "high")
+		cmd.SetIn(strings.NewReader("Plan sprint\n\n\n\n"))
+		defer cmd.SetIn(nil)
+
+		output := runCommand(t, "add", "--interactive")
+		assert.Contains(t, output, "Priority (low, medium, high) [high]")
+		assert.Equal(t, "high", priorityOf(t, "Plan sprint"))
+	})
+
+	t.Run("invalid default is rejected", func(t *testing.T) {
+		clearTestTasks(t)
+		t.Setenv("TASKER_DEFAULT_PRIORITY", "urgent")
+		output := runCommand(t, "add", "Water plants")
+		assert.Contains(t, output, `Error adding task: $TASKER_DEFAULT_PRIORITY: invalid priority "urgent" (valid: low, medium, high)`)
+		assert.Equal(t, 0, getTaskCount(t))
+	})
+}
+
+func TestAddTaskDone(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	// completion reads the completion state and time of the task titled title
+	completion := func(t *testing.T, title string) (bool, sql.NullTime, time.Time) {
+		var done bool
+		var completedAt sql.NullTime
+		var createdAt time.Time
+		err := database.GetDB().QueryRow(`SELECT done, completed_at, created_at FROM tasks WHERE title = ?`, title).
+			Scan(&done, &completedAt, &createdAt)
+		require.NoError(t, err)
+		return done, completedAt, createdAt
+	}
+
+	t.Run("task is added completed now", func(t *testing.T) {
+		clearTestTasks(t)
+		before := time.Now()
+		output := runCommand(t, "add", "Reviewed the contract", "--done")
+		assert.Contains(t, output, "Task added as done: Reviewed the contract")
+
+		done, completedAt, _ := completion(t, "Reviewed the contract")
+		assert.True(t, done)
+		require.True(t, completedAt.Valid)
+		assert.WithinDuration(t, before, completedAt.Time, 5*time.Second)
+	})
+
+	t.Run("completion time can be given", func(t *testing.T) {
+		clearTestTasks(t)
+		runCommand(t, "add", "Paid the bill", "--done", "--completed-at", "2024-06-01 14:30")
+
+		done, completedAt, createdAt := completion(t, "Paid the bill")
+		assert.True(t, done)
+		require.True(t, completedAt.Valid)
+		expected := time.Date(2024, 6, 1, 14, 30, 0, 0, time.Local)
+		assert.True(t, expected.Equal(completedAt.Time), "completed at %v", completedAt.Time)
+		assert.True(t, expected.Equal(createdAt), "created at %v", createdAt)
+	})
+
+	t.Run("backdated task counts as modified now", func(t *testing.T) {
+		clearTestTasks(t)
+		runCommand(t, "add", "Old invoice", "--done", "--completed-at", "2024-01-15")
+		ids := listedTaskIDs(t, runCommand(t, "list", "--sort", "id"))
+		require.Len(t, ids, 1)
+
+		output := runCommand(t, "list", "--modified-since", "10m")
+		assert.Equal(t, ids, listedTaskIDs(t, output))
+	})
+
+	t.Run("without --done the task is pending", func(t *testing.T) {
+		clearTestTasks(t)
+		runCommand(t, "add", "Water plants")
+
+		done, completedAt, _ := completion(t, "Water plants")
+		assert.False(t, done)
+		assert.False(t, completedAt.Valid)
+	})
+
+	t.Run("invalid completion times are rejected", func(t *testing.T) {
+		clearTestTasks(t)
+		output := runCommand(t, "add", "Someday", "--completed-at", "yesterday")
+		assert.Contains(t, output, "Error adding task: --completed-at requires --done")
+
+		output = runCommand(t, "add", "Someday", "--done", "--completed-at", "not a date")
+		assert.Contains(t, output, "Error adding task: invalid --completed-at")
+
+		output = runCommand(t, "add", "Someday", "--done", "--completed-at", time.Now().Add(48*time.Hour).Format("2006-01-02"))
+		assert.Contains(t, output, "Error adding task: --completed-at cannot be in the future")
+
+		assert.Equal(t, 0, getTaskCount(t))
+	})
+}
+
+func TestAddTaskInteractive(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+	defer cmd.SetIn(nil)
+
+	type added struct {
+		Title, Description, Priority string
+		DueDate                      *time.Time
+	}
+	lastTask := func(t *testing.T) added {
+		var task added
+		err := database.GetDB().QueryRow(`SELECT title, description, priority, due_date FROM tasks ORDER BY id DESC LIMIT 1`).
+			Scan(&task.Title, &task.Description, &task.Priority, &task.DueDate)
+		require.NoError(t, err)
+		return task
+	}
+
+	t.Run("all fields answered", func(t *testing.T) {
+		clearTestTasks(t)
+		cmd.SetIn(strings.NewReader("Plan trip\nBook flights and hotel\nhigh\n2030-03-01 10:00\n"))
+		output := runCommand(t, "add", "--interactive")
+
+		assert.Contains(t, output, "Title: ")
+		assert.Contains(t, output, "Priority (low, medium, high) [medium]: ")
+		assert.Contains(t, output, "✓ Task added: Plan trip")
+
+		task := lastTask(t)
+		assert.Equal(t, "Plan trip", task.Title)
+		assert.Equal(t, "Book flights and hotel", task.Description)
+		assert.Equal(t, "high", task.Priority)
+		require.NotNil(t, task.DueDate)
+		assert.Equal(t, time.Date(2030, 3, 1, 10, 0, 0, 0, time.Local), task.DueDate.Local())
+	})
+
+	t.Run("optional fields skipped", func(t *testing.T) {
+		clearTestTasks(t)
+		cmd.SetIn(strings.NewReader("Water plants\n\n\n\n"))
+		output := runCommand(t, "add", "-i")
+		assert.Contains(t, output, "✓ Task added: Water plants")
+
+		task := lastTask(t)
+		assert.Equal(t, "", task.Description)
+		assert.Equal(t, "medium", task.Priority)
+		assert.Nil(t, task.DueDate)
+	})
+
+	t.Run("invalid answers are asked again", func(t *testing.T) {
+		clearTestTasks(t)
+		cmd.SetIn(strings.NewReader("\nCall the bank\n\nurgent\nlow\nsomeday\n2030-01-02\n"))
+		output := runCommand(t, "add", "--interactive")
+
+		assert.Contains(t, output, "Invalid answer: title cannot be empty")
+		assert.Contains(t, output, `Invalid answer: invalid priority "urgent"`)
+		assert.Contains(t, output, `Invalid answer: invalid due date "someday"`)
+		assert.Contains(t, output, "✓ Task added: Call the bank")
+
+		task := lastTask(t)
+		assert.Equal(t, "low", task.Priority)
+		require.NotNil(t, task.DueDate)
+		assert.Equal(t, "2030-01-02", task.DueDate.Local().Format("2006-01-02"))
+	})
+
+	t.Run("input ending early adds nothing", func(t *testing.T) {
+		clearTestTasks(t)
+		cmd.SetIn(strings.NewReader("Half answered\n"))
+		output := runCommand(t, "add", "--interactive")
+
+		assert.Contains(t, output, "Error adding task: input ended before all fields were answered")
+
+		var count int
+		require.NoError(t, database.GetDB().QueryRow(`SELECT COUNT(*) FROM tasks`).Scan(&count))
+		assert.Zero(t, count)
+	})
+
+	t.Run("title argument is rejected", func(t *testing.T) {
+		_, err := runCommandErr(t, "add", "Title", "--interactive")
+		assert.ErrorContains(t, err, "--interactive asks for the title")
+	})
+}
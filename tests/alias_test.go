@@ -0,0 +1,94 @@
+package tests
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/eduardamirelly/tasker/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAliases(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	// Aliases are saved to a config file that doesn't exist yet
+	t.Setenv(config.PathEnv, filepath.Join(t.TempDir(), "tasker", "config.json"))
+
+	clearTestTasks(t)
+	insertTestTask(t, "Fix login bug", "", false)
+	insertTestTask(t, "Fix export bug", "", false)
+	insertTestTask(t, "Add dark mode", "", false)
+	insertTestTask(t, "Fix import bug", "", true)
+
+	t.Run("save and run", func(t *testing.T) {
+		output := runCommand(t, "alias", "save", "bugs", `list --title-contains "bug" --sort title --limit 2`)
+		assert.Contains(t, output, `✓ Alias saved: bugs = list --title-contains "bug" --sort title --limit 2`)
+
+		cfg, err := config.Load()
+		require.NoError(t, err)
+		assert.Equal(t, `list --title-contains "bug" --sort title --limit 2`, cfg.Aliases["bugs"])
+
+		output = runCommand(t, "bugs")
+		assert.Contains(t, output, "Fix export bug")
+		assert.Contains(t, output, "Fix import bug")
+		assert.NotContains(t, output, "Fix login bug")
+		assert.NotContains(t, output, "Add dark mode")
+	})
+
+	t.Run("extra arguments are appended", func(t *testing.T) {
+		assert.Equal(t, "2\n", runCommand(t, "bugs", "--pending-count"))
+	})
+
+	t.Run("global flags may come before the alias", func(t *testing.T) {
+		assert.Equal(t, "2\n", runCommand(t, "--strict", "bugs", "--pending-count"))
+		assert.Equal(t, "2\n", runCommand(t, "--no-emoji", "-q", "bugs", "--pending-count"))
+		assert.Equal(t, "2\n", runCommand(t, "--log-format", "json", "bugs", "--pending-count"))
+		assert.Equal(t, "2\n", runCommand(t, "--log-format=text", "--quiet=true", "bugs", "--pending-count"))
+
+		_, err := runCommandErr(t, "--pending-count", "bugs")
+		assert.Error(t, err, "a flag that isn't global stops the expansion")
+	})
+
+	t.Run("quoted arguments keep their spaces", func(t *testing.T) {
+		runCommand(t, "alias", "save", "login", `list --title-contains 'login bug' --count`)
+		assert.Equal(t, "1\n", runCommand(t, "login"))
+	})
+
+	t.Run("list and remove", func(t *testing.T) {
+		output := runCommand(t, "alias", "list")
+		assert.Contains(t, output, fmt.Sprintf("%-20s %s\n", "bugs", `list --title-contains "bug" --sort title --limit 2`))
+		assert.Contains(t, output, fmt.Sprintf("%-20s %s\n", "login", `list --title-contains 'login bug' --count`))
+
+		assert.Contains(t, runCommand(t, "alias", "remove", "login"), "✓ Alias removed: login")
+		assert.NotContains(t, runCommand(t, "alias", "list"), "login")
+		assert.Contains(t, runCommand(t, "alias", "remove", "login"), "❌ Alias not found: login")
+
+		_, err := runCommandErr(t, "login")
+		assert.Error(t, err, "removed aliases are unknown commands")
+	})
+
+	t.Run("invalid aliases are rejected", func(t *testing.T) {
+		cases := map[string][]string{
+			`"list" is already a tasker command`:               {"list", "list --count"},
+			"must start with a tasker command, not \"bugs\"":   {"mine", "bugs --count"},
+			`must start with a tasker command, not "nonsense"`: {"mine", "nonsense"},
+			"the command line cannot be empty":                 {"mine", "  "},
+			"unterminated \" quote":                            {"mine", `list --title-contains "bug`},
+			`invalid alias name "My Day"`:                      {"My Day", "list"},
+		}
+		for message, args := range cases {
+			output := runCommand(t, append([]string{"alias", "save"}, args...)...)
+			assert.Contains(t, output, "Error saving alias: ")
+			assert.Contains(t, output, message)
+		}
+
+		data, err := os.ReadFile(os.Getenv(config.PathEnv))
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), "mine")
+	})
+}
@@ -0,0 +1,80 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/eduardamirelly/tasker/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttachments(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+	id := fmt.Sprint(insertTestTask(t, "Review report", "", false))
+
+	t.Run("attach", func(t *testing.T) {
+		output := runCommand(t, "attach", id, "https://example.com/issues/42", "--label", "issue")
+		assert.Contains(t, output, "✓ Attached to Review report: issue: https://example.com/issues/42")
+
+		output = runCommand(t, "attach", id, "/home/me/report.pdf")
+		assert.Contains(t, output, "✓ Attached to Review report: /home/me/report.pdf")
+
+		output = runCommand(t, "attach", id, "/home/me/report.pdf")
+		assert.Contains(t, output, "❌ Already attached to task "+id+": /home/me/report.pdf")
+	})
+
+	t.Run("show lists the attachments", func(t *testing.T) {
+		output := runCommand(t, "show", id)
+		assert.Contains(t, output, "Attachments:\n  - issue: https://example.com/issues/42\n  - /home/me/report.pdf\n")
+
+		output = runCommand(t, "show", id, "--pretty")
+		assert.Contains(t, output, fmt.Sprintf("%-18s%s\n", "Attachments:", "issue: https://example.com/issues/42, /home/me/report.pdf"))
+	})
+
+	t.Run("detach by label and by path", func(t *testing.T) {
+		output := runCommand(t, "detach", id, "issue")
+		assert.Contains(t, output, "✓ Detached from Review report: issue")
+
+		output = runCommand(t, "show", id)
+		assert.NotContains(t, output, "issues/42")
+		assert.Contains(t, output, "  - /home/me/report.pdf\n")
+
+		runCommand(t, "detach", id, "/home/me/report.pdf")
+		output = runCommand(t, "show", id)
+		assert.NotContains(t, output, "Attachments:")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		assert.Contains(t, runCommand(t, "detach", id, "missing"), `❌ No attachment "missing" on task `+id)
+		assert.Contains(t, runCommand(t, "attach", "99999", "https://example.com"), "❌ Task not found: 99999")
+		assert.Contains(t, runCommand(t, "attach", id, "  "), "the path or URL cannot be empty")
+	})
+}
+
+func TestAttachmentsMarkTaskModified(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+	longAgo := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	id := insertTestTaskWithSpecificTime(t, "Old task", "", false, longAgo, nil)
+	assert.Empty(t, listedTaskIDs(t, runCommand(t, "list", "--modified-since", "10m")))
+
+	runCommand(t, "attach", fmt.Sprint(id), "https://example.com/spec", "--label", "spec")
+	assert.Equal(t, []int{id}, listedTaskIDs(t, runCommand(t, "list", "--modified-since", "10m")))
+
+	_, err := database.GetDB().Exec(`UPDATE tasks SET updated_at = ? WHERE id = ?`, longAgo, id)
+	require.NoError(t, err)
+	runCommand(t, "detach", fmt.Sprint(id), "missing")
+	assert.Empty(t, listedTaskIDs(t, runCommand(t, "list", "--modified-since", "10m")), "nothing was detached")
+
+	runCommand(t, "detach", fmt.Sprint(id), "spec")
+	assert.Equal(t, []int{id}, listedTaskIDs(t, runCommand(t, "list", "--modified-since", "10m")))
+}
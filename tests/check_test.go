@@ -0,0 +1,77 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/eduardamirelly/tasker/cmd"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckOverdueExec(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	runner := &recordingRunner{}
+	previous := cmd.SetHookRunner(runner.run)
+	defer cmd.SetHookRunner(previous)
+
+	clearTestTasks(t)
+	yesterday := time.Now().Add(-24 * time.Hour)
+	lastWeek := time.Now().Add(-7 * 24 * time.Hour)
+	tomorrow := time.Now().Add(24 * time.Hour)
+	rentID := insertTestTaskWithDueDate(t, "Pay rent", false, &yesterday)
+	passportID := insertTestTaskWithDueDate(t, `Renew passport; echo "injected" $(rm -rf ~) | tee 'x' && exit`, false, &lastWeek)
+	insertTestTaskWithDueDate(t, "Already paid", true, &yesterday)
+	insertTestTaskWithDueDate(t, "Due tomorrow", false, &tomorrow)
+	insertTestTaskWithDueDate(t, "No due date", false, nil)
+
+	runCommand(t, "check", "--overdue", "--exec", `notify-send "Overdue task" {{.Title}} --id={{.ID}}`)
+
+	assert.ElementsMatch(t, [][]string{
+		{"notify-send", "Overdue task", "Pay rent", fmt.Sprintf("--id=%d", rentID)},
+		{"notify-send", "Overdue task", `Renew passport; echo "injected" $(rm -rf ~) | tee 'x' && exit`, fmt.Sprintf("--id=%d", passportID)},
+	}, runner.calls, "each title stays a single argument")
+}
+
+func TestCheckWithoutExec(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+	yesterday := time.Now().Add(-24 * time.Hour)
+	insertTestTaskWithDueDate(t, "Pay rent", false, &yesterday)
+	insertTestTaskWithDueDate(t, "No due date", false, nil)
+
+	output := runCommand(t, "check", "--overdue")
+	assert.Contains(t, output, "1 task(s) match")
+	assert.Contains(t, output, "Pay rent")
+	assert.NotContains(t, output, "No due date")
+
+	output = runCommand(t, "check")
+	assert.Contains(t, output, "provide at least one filter flag")
+}
+
+func TestCheckSharedFilters(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+	runCommand(t, "add", "Fix login bug", "--tag", "work", "--priority", "high")
+	runCommand(t, "add", "Write report", "--tag", "work", "--priority", "low")
+	runCommand(t, "add", "Call plumber", "--tag", "home", "--priority", "high")
+
+	output := runCommand(t, "check", "--tag", "work", "--priority", "high")
+	assert.Contains(t, output, "1 task(s) match")
+	assert.Contains(t, output, "Fix login bug")
+
+	output = runCommand(t, "check", "--status", "pending", "--modified-since", "1h")
+	assert.Contains(t, output, "3 task(s) match")
+
+	output = runCommand(t, "check", "--status", "later")
+	assert.Contains(t, output, `Error: invalid status "later"`)
+}
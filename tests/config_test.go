@@ -0,0 +1,34 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/eduardamirelly/tasker/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigLoad(t *testing.T) {
+	t.Run("missing file yields an empty config", func(t *testing.T) {
+		t.Setenv(config.PathEnv, "/nonexistent/tasker/config.json")
+
+		cfg, err := config.Load()
+		require.NoError(t, err)
+		assert.Empty(t, cfg.CommandDefaults("list"))
+	})
+
+	t.Run("command defaults", func(t *testing.T) {
+		writeTestConfig(t, `{"defaults": {"list": {"sort": "title", "reverse": "true"}}}`)
+
+		cfg, err := config.Load()
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"sort": "title", "reverse": "true"}, cfg.CommandDefaults("list"))
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		writeTestConfig(t, `{"defaults": `)
+
+		_, err := config.Load()
+		assert.Error(t, err)
+	})
+}
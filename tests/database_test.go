@@ -0,0 +1,364 @@
+package tests
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/eduardamirelly/tasker/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetDBAndGetDB(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	firstDB := database.GetDB()
+	require.NotNil(t, firstDB)
+	insertTestTask(t, "Task in first database", "", false)
+
+	// Swap in a second, independent database
+	secondDB, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "second.db"))
+	require.NoError(t, err)
+
+	previous := database.SetDB(secondDB)
+	assert.Same(t, firstDB, previous)
+	assert.Same(t, secondDB, database.GetDB())
+
+	require.NoError(t, database.Migrate())
+	insertTestTask(t, "Task in second database", "", false)
+
+	// Commands pick up the swapped database
+	output := runCommand(t, "list")
+	assert.Contains(t, output, "Task in second database")
+	assert.NotContains(t, output, "Task in first database")
+
+	// Restoring the original database brings its tasks back
+	database.SetDB(previous).Close()
+	output = runCommand(t, "list")
+	assert.Contains(t, output, "Task in first database")
+	assert.NotContains(t, output, "Task in second database")
+}
+
+func TestGetDBConcurrentSwaps(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	original := database.GetDB()
+	other, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "other.db"))
+	require.NoError(t, err)
+	defer other.Close()
+	defer database.SetDB(original)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			database.SetDB(other)
+			database.SetDB(original)
+		}
+	}()
+
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				current := database.GetDB()
+				if current != original && current != other {
+					assert.Fail(t, "GetDB returned a handle that was never set")
+					return
+				}
+				assert.NoError(t, current.Ping())
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Same(t, original, database.GetDB())
+}
+
+func TestMigrateBackfillsNewColumns(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	// Start from a database created before updated_at existed
+	oldDB, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "old.db"))
+	require.NoError(t, err)
+	previous := database.SetDB(oldDB)
+	defer func() { database.SetDB(previous).Close() }()
+
+	_, err = oldDB.Exec(`CREATE TABLE tasks (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		title TEXT NOT NULL,
+		description TEXT,
+		done BOOLEAN DEFAULT FALSE,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		completed_at DATETIME
+	)`)
+	require.NoError(t, err)
+	_, err = oldDB.Exec(`INSERT INTO tasks (title, created_at) VALUES ('Old task', '2024-01-02 03:04:05')`)
+	require.NoError(t, err)
+
+	require.NoError(t, database.Migrate())
+
+	var updatedAt string
+	require.NoError(t, oldDB.QueryRow(`SELECT datetime(updated_at) FROM tasks`).Scan(&updatedAt))
+	assert.Equal(t, "2024-01-02 03:04:05", updatedAt)
+
+	var uuid string
+	require.NoError(t, oldDB.QueryRow(`SELECT uuid FROM tasks`).Scan(&uuid))
+	assert.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, uuid)
+}
+
+func TestInitDBIsIdempotent(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	// InitDB opens tasker.db in the working directory
+	t.Chdir(t.TempDir())
+	previous := database.SetDB(nil)
+	defer func() {
+		database.CloseDB()
+		database.SetDB(previous)
+	}()
+
+	require.NoError(t, database.InitDB())
+	first := database.GetDB()
+	require.NotNil(t, first)
+
+	require.NoError(t, database.InitDB())
+	assert.Same(t, first, database.GetDB(), "a second InitDB must reuse the connection")
+
+	runCommand(t, "add", "Persisted task")
+	assert.Equal(t, 1, getTaskCount(t))
+
+	// Closing forces the next InitDB to open a fresh connection to the same file
+	require.NoError(t, database.CloseDB())
+	assert.Nil(t, database.GetDB())
+
+	require.NoError(t, database.InitDB())
+	assert.NotSame(t, first, database.GetDB())
+	assert.Equal(t, 1, getTaskCount(t))
+}
+
+func TestMigrateAddsMissingTimestamps(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	// A very early schema without created_at and completed_at
+	oldDB, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "early.db"))
+	require.NoError(t, err)
+	previous := database.SetDB(oldDB)
+	defer func() { database.SetDB(previous).Close() }()
+
+	_, err = oldDB.Exec(`CREATE TABLE tasks (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		title TEXT NOT NULL,
+		description TEXT,
+		done BOOLEAN DEFAULT FALSE
+	)`)
+	require.NoError(t, err)
+	_, err = oldDB.Exec(`INSERT INTO tasks (title, description, done) VALUES ('Early task', '', FALSE), ('Early done task', '', TRUE)`)
+	require.NoError(t, err)
+
+	require.NoError(t, database.Migrate())
+
+	var missing int
+	require.NoError(t, oldDB.QueryRow(`SELECT COUNT(*) FROM tasks WHERE created_at IS NULL`).Scan(&missing))
+	assert.Zero(t, missing, "existing rows get a creation time")
+
+	output := runCommand(t, "list")
+	assert.Contains(t, output, "Early task")
+	assert.Contains(t, output, "Early done task")
+
+	output = runCommand(t, "done", "1")
+	assert.Contains(t, output, "✓ Task marked as done: Early task")
+}
+
+func TestInitCommand(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	// Without an open connection, init creates tasker.db in the working directory
+	dir := t.TempDir()
+	t.Chdir(dir)
+	previous := database.SetDB(nil)
+	defer func() {
+		database.CloseDB()
+		database.SetDB(previous)
+	}()
+
+	dbPath, err := filepath.EvalSymlinks(dir)
+	require.NoError(t, err)
+	dbPath = filepath.Join(dbPath, "tasker.db")
+
+	output := runCommand(t, "init")
+	assert.Contains(t, output, "✓ Database ready: ")
+	assert.Contains(t, output, "tasker.db")
+	_, err = os.Stat(dbPath)
+	require.NoError(t, err, "the database file is created")
+
+	tables := func() []string {
+		rows, err := database.GetDB().Query(`SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name`)
+		require.NoError(t, err)
+		defer rows.Close()
+
+		var names []string
+		for rows.Next() {
+			var name string
+			require.NoError(t, rows.Scan(&name))
+			names = append(names, name)
+		}
+		require.NoError(t, rows.Err())
+		return names
+	}
+	assert.Equal(t, []string{"app_state", "task_attachments", "task_tags", "tasks"}, tables())
+
+	runCommand(t, "add", "Survives a second init")
+
+	t.Run("safe to run twice", func(t *testing.T) {
+		output := runCommand(t, "init")
+		assert.Contains(t, output, "✓ Database ready: ")
+		assert.Equal(t, []string{"app_state", "task_attachments", "task_tags", "tasks"}, tables())
+		assert.Equal(t, 1, getTaskCount(t))
+	})
+}
+
+func TestReadOnlyDatabase(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	// Build an up to date database, then reopen it read-only
+	dbPath := filepath.Join(t.TempDir(), "readonly.db")
+	writable, err := sql.Open("sqlite3", dbPath)
+	require.NoError(t, err)
+	previous := database.SetDB(writable)
+	require.NoError(t, database.Migrate())
+	runCommand(t, "add", "Existing task")
+	require.NoError(t, writable.Close())
+
+	readOnly, err := sql.Open("sqlite3", "file:"+dbPath+"?mode=ro")
+	require.NoError(t, err)
+	database.SetDB(readOnly)
+	defer func() { database.SetDB(previous).Close() }()
+
+	require.NoError(t, database.Migrate(), "an up to date database needs no changes")
+
+	t.Run("add fails with a clear message", func(t *testing.T) {
+		output := runCommand(t, "add", "New task")
+		assert.Contains(t, output, "Error adding task: the database "+dbPath+" is read-only")
+		assert.NotContains(t, output, "attempt to write")
+		assert.Equal(t, 1, getTaskCount(t))
+	})
+
+	t.Run("done fails with a clear message", func(t *testing.T) {
+		output := runCommand(t, "done", "1")
+		assert.Contains(t, output, "is read-only")
+		assert.False(t, getTaskByID(t, 1).Done)
+	})
+
+	t.Run("read commands still work", func(t *testing.T) {
+		output := runCommand(t, "list")
+		assert.Contains(t, output, "Existing task")
+		assert.NotContains(t, output, "Error")
+
+		output = runCommand(t, "show", "1")
+		assert.Contains(t, output, "Existing task")
+
+		outputFile := filepath.Join(t.TempDir(), "tasks.csv")
+		output = runCommand(t, "export", "-o", outputFile)
+		assert.NotContains(t, output, "Error")
+		assert.Contains(t, output, "the export time was not recorded")
+		_, err := os.Stat(outputFile)
+		assert.NoError(t, err)
+	})
+}
+
+func TestWhereCommand(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	dir := t.TempDir()
+	t.Chdir(dir)
+	dir, err := filepath.EvalSymlinks(dir)
+	require.NoError(t, err)
+
+	t.Run("prints the path given with --db", func(t *testing.T) {
+		dbPath := filepath.Join(t.TempDir(), "work.db")
+		output := runCommand(t, "where", "--db", dbPath)
+		assert.Contains(t, output, "Database: "+dbPath+"\n")
+		assert.Contains(t, output, "Source:   --db\n")
+		assert.Contains(t, output, "Exists:   no")
+
+		_, err := os.Stat(dbPath)
+		assert.True(t, os.IsNotExist(err), "where doesn't create the database")
+	})
+
+	t.Run("reports the size of an existing file", func(t *testing.T) {
+		dbPath := filepath.Join(t.TempDir(), "existing.db")
+		require.NoError(t, os.WriteFile(dbPath, make([]byte, 4096), 0o644))
+
+		output := runCommand(t, "where", "--db", dbPath)
+		assert.Contains(t, output, "Exists:   yes\nSize:     4096 bytes\n")
+	})
+
+	t.Run("relative paths are resolved", func(t *testing.T) {
+		output := runCommand(t, "where", "--db", "tasks/my.db")
+		assert.Contains(t, output, "Database: "+filepath.Join(dir, "tasks", "my.db")+"\n")
+	})
+
+	t.Run("precedence", func(t *testing.T) {
+		envPath := filepath.Join(dir, "env.db")
+		configPath := filepath.Join(dir, "config.db")
+		writeTestConfig(t, `{"database": "`+configPath+`"}`)
+
+		output := runCommand(t, "where")
+		assert.Contains(t, output, "Database: "+configPath+"\nSource:   config file\n")
+
+		t.Setenv("TASKER_DB", envPath)
+		output = runCommand(t, "where")
+		assert.Contains(t, output, "Database: "+envPath+"\nSource:   $TASKER_DB\n")
+
+		output = runCommand(t, "where", "--db", "flag.db")
+		assert.Contains(t, output, "Database: "+filepath.Join(dir, "flag.db")+"\nSource:   --db\n")
+	})
+
+	t.Run("default is the working directory", func(t *testing.T) {
+		writeTestConfig(t, `{}`)
+		output := runCommand(t, "where")
+		assert.Contains(t, output, "Database: "+filepath.Join(dir, "tasker.db")+"\nSource:   default (working directory)\n")
+	})
+
+	t.Run("commands use the database given with --db", func(t *testing.T) {
+		previous := database.SetDB(nil)
+		defer func() {
+			database.CloseDB()
+			database.SetFilePath("")
+			database.SetDB(previous)
+		}()
+
+		dbPath := filepath.Join(t.TempDir(), "flag.db")
+		runCommand(t, "add", "Stored elsewhere", "--db", dbPath)
+
+		path, err := database.Path()
+		require.NoError(t, err)
+		assert.Equal(t, dbPath, path)
+		assert.Equal(t, 1, getTaskCount(t))
+
+		output := runCommand(t, "where", "--db", dbPath)
+		assert.Contains(t, output, "Exists:   yes")
+	})
+}
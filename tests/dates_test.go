@@ -0,0 +1,204 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/eduardamirelly/tasker/dates"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWeekBounds(t *testing.T) {
+	// Wednesday 2024-05-15
+	wednesday := time.Date(2024, 5, 15, 16, 20, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		t             time.Time
+		weekStart     time.Weekday
+		expectedStart time.Time
+	}{
+		{
+			name:          "monday week start",
+			t:             wednesday,
+			weekStart:     time.Monday,
+			expectedStart: time.Date(2024, 5, 13, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "sunday week start",
+			t:             wednesday,
+			weekStart:     time.Sunday,
+			expectedStart: time.Date(2024, 5, 12, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "first day of the week",
+			t:             time.Date(2024, 5, 13, 0, 0, 0, 0, time.UTC),
+			weekStart:     time.Monday,
+			expectedStart: time.Date(2024, 5, 13, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "week spanning a year boundary",
+			t:             time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC),
+			weekStart:     time.Monday,
+			expectedStart: time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := dates.WeekBounds(tt.t, tt.weekStart)
+			assert.Equal(t, tt.expectedStart, start)
+			assert.Equal(t, tt.expectedStart.AddDate(0, 0, 7), end)
+		})
+	}
+}
+
+func TestMonthBounds(t *testing.T) {
+	tests := []struct {
+		name          string
+		t             time.Time
+		expectedStart time.Time
+		expectedEnd   time.Time
+	}{
+		{
+			name:          "middle of a month",
+			t:             time.Date(2024, 5, 15, 16, 20, 0, 0, time.UTC),
+			expectedStart: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "last moment of a month",
+			t:             time.Date(2024, 1, 31, 23, 59, 59, 0, time.UTC),
+			expectedStart: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "leap february",
+			t:             time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC),
+			expectedStart: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "december ends in the next year",
+			t:             time.Date(2024, 12, 31, 9, 0, 0, 0, time.UTC),
+			expectedStart: time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := dates.MonthBounds(tt.t)
+			assert.Equal(t, tt.expectedStart, start)
+			assert.Equal(t, tt.expectedEnd, end)
+		})
+	}
+}
+
+func TestParseMonth(t *testing.T) {
+	loc := time.FixedZone("UTC-3", -3*60*60)
+
+	start, end, err := dates.ParseMonth("2023-12", loc)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2023, 12, 1, 0, 0, 0, 0, loc), start)
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, loc), end)
+
+	for _, value := range []string{"2023-13", "2023/12", "december", "2023-12-01"} {
+		_, _, err := dates.ParseMonth(value, loc)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestWeekStart(t *testing.T) {
+	t.Setenv(dates.WeekStartEnv, "")
+	assert.Equal(t, time.Monday, dates.WeekStart())
+
+	t.Setenv(dates.WeekStartEnv, "Sunday")
+	assert.Equal(t, time.Sunday, dates.WeekStart())
+
+	t.Setenv(dates.WeekStartEnv, "not-a-day")
+	assert.Equal(t, time.Monday, dates.WeekStart())
+}
+
+func TestParseDue(t *testing.T) {
+	// Wednesday 2024-05-15 10:00
+	now := time.Date(2024, 5, 15, 10, 0, 0, 0, time.UTC)
+	endOfDay := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 23, 59, 59, 0, time.UTC)
+	}
+
+	tests := []struct {
+		input    string
+		expected time.Time
+	}{
+		{"2024-06-01", endOfDay(2024, 6, 1)},
+		{"2024-06-01 14:30", time.Date(2024, 6, 1, 14, 30, 0, 0, time.UTC)},
+		{"today", endOfDay(2024, 5, 15)},
+		{"Tomorrow", endOfDay(2024, 5, 16)},
+		{"tomorrow 5pm", time.Date(2024, 5, 16, 17, 0, 0, 0, time.UTC)},
+		{"tomorrow 9:30 am", time.Date(2024, 5, 16, 9, 30, 0, 0, time.UTC)},
+		{"today 12am", time.Date(2024, 5, 15, 0, 0, 0, 0, time.UTC)},
+		{"today 17:45", time.Date(2024, 5, 15, 17, 45, 0, 0, time.UTC)},
+		{"next monday", endOfDay(2024, 5, 20)},
+		{"next wednesday", endOfDay(2024, 5, 22)},
+		{"fri 8am", time.Date(2024, 5, 17, 8, 0, 0, 0, time.UTC)},
+		{"in 3 days", endOfDay(2024, 5, 18)},
+		{"in 1 week", endOfDay(2024, 5, 22)},
+		{"in 2 hours", time.Date(2024, 5, 15, 12, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := dates.ParseDue(tt.input, now)
+			assert.NoError(t, err)
+			assert.True(t, tt.expected.Equal(got), "expected %v, got %v", tt.expected, got)
+		})
+	}
+
+	for _, input := range []string{"", "someday", "tomorrow 25:00", "today 13pm", "next month"} {
+		t.Run("invalid "+input, func(t *testing.T) {
+			_, err := dates.ParseDue(input, now)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParseSinceAndUntil(t *testing.T) {
+	// Wednesday 2024-05-15 10:00
+	now := time.Date(2024, 5, 15, 10, 0, 0, 0, time.UTC)
+	midnight := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		input string
+		since time.Time
+		until time.Time
+	}{
+		{"2024-03-01", midnight(2024, 3, 1), midnight(2024, 3, 2)},
+		{"2024-03-01 14:30", time.Date(2024, 3, 1, 14, 30, 0, 0, time.UTC), time.Date(2024, 3, 1, 14, 30, 0, 0, time.UTC)},
+		{"today", midnight(2024, 5, 15), midnight(2024, 5, 16)},
+		{"Yesterday", midnight(2024, 5, 14), midnight(2024, 5, 15)},
+		{"24h", time.Date(2024, 5, 14, 10, 0, 0, 0, time.UTC), time.Date(2024, 5, 14, 10, 0, 0, 0, time.UTC)},
+		{"30d", time.Date(2024, 4, 15, 10, 0, 0, 0, time.UTC), time.Date(2024, 4, 15, 10, 0, 0, 0, time.UTC)},
+		{"2w", time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			since, err := dates.ParseSince(tt.input, now)
+			require.NoError(t, err)
+			assert.True(t, tt.since.Equal(since), "since: expected %v, got %v", tt.since, since)
+
+			until, err := dates.ParseUntil(tt.input, now)
+			require.NoError(t, err)
+			assert.True(t, tt.until.Equal(until), "until: expected %v, got %v", tt.until, until)
+		})
+	}
+
+	for _, input := range []string{"", "last quarter", "-2d", "3 days"} {
+		_, err := dates.ParseSince(input, now)
+		assert.Error(t, err, input)
+	}
+}
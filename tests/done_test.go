@@ -1,10 +1,16 @@
 package tests
 
 import (
+	"database/sql"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/eduardamirelly/tasker/cmd"
 	"github.com/eduardamirelly/tasker/database"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -28,7 +34,7 @@ func TestFindTaskById(t *testing.T) {
 		var done bool
 		var createdAt time.Time
 
-		err := database.DB.QueryRow(query, taskID).Scan(&id, &title, &description, &done, &createdAt)
+		err := database.GetDB().QueryRow(query, taskID).Scan(&id, &title, &description, &done, &createdAt)
 		require.NoError(t, err)
 
 		assert.Equal(t, taskID, id)
@@ -47,7 +53,7 @@ func TestFindTaskById(t *testing.T) {
 		var title, description string
 		var done bool
 
-		err := database.DB.QueryRow(query, 999).Scan(&id, &title, &description, &done)
+		err := database.GetDB().QueryRow(query, 999).Scan(&id, &title, &description, &done)
 		assert.Error(t, err) // Should be sql.ErrNoRows
 	})
 
@@ -60,7 +66,7 @@ func TestFindTaskById(t *testing.T) {
 		// Set completed_at timestamp
 		completedTime := time.Now()
 		updateQuery := `UPDATE tasks SET completed_at = ? WHERE id = ?`
-		_, err := database.DB.Exec(updateQuery, completedTime, taskID)
+		_, err := database.GetDB().Exec(updateQuery, completedTime, taskID)
 		require.NoError(t, err)
 
 		// Find the task
@@ -70,7 +76,7 @@ func TestFindTaskById(t *testing.T) {
 		var done bool
 		var completedAt time.Time
 
-		err = database.DB.QueryRow(query, taskID).Scan(&id, &title, &done, &completedAt)
+		err = database.GetDB().QueryRow(query, taskID).Scan(&id, &title, &done, &completedAt)
 		require.NoError(t, err)
 
 		assert.Equal(t, taskID, id)
@@ -98,7 +104,7 @@ func TestMarkTaskAsDone(t *testing.T) {
 		// Mark as done using direct database update (simulating the cmd function)
 		completedTime := time.Now()
 		updateQuery := `UPDATE tasks SET done = TRUE, completed_at = ? WHERE id = ?`
-		_, err := database.DB.Exec(updateQuery, completedTime, taskID)
+		_, err := database.GetDB().Exec(updateQuery, completedTime, taskID)
 		require.NoError(t, err)
 
 		// Verify task is now marked as done
@@ -109,7 +115,7 @@ func TestMarkTaskAsDone(t *testing.T) {
 		// Verify completed_at timestamp was set
 		var completedAt *time.Time
 		query := `SELECT completed_at FROM tasks WHERE id = ?`
-		err = database.DB.QueryRow(query, taskID).Scan(&completedAt)
+		err = database.GetDB().QueryRow(query, taskID).Scan(&completedAt)
 		require.NoError(t, err)
 		assert.NotNil(t, completedAt)
 		assert.WithinDuration(t, time.Now(), *completedAt, 5*time.Second)
@@ -124,7 +130,7 @@ func TestMarkTaskAsDone(t *testing.T) {
 		// Set completed_at timestamp
 		originalTime := time.Now().Add(-time.Hour)
 		updateQuery := `UPDATE tasks SET completed_at = ? WHERE id = ?`
-		_, err := database.DB.Exec(updateQuery, originalTime, taskID)
+		_, err := database.GetDB().Exec(updateQuery, originalTime, taskID)
 		require.NoError(t, err)
 
 		// Verify task is completed
@@ -135,13 +141,13 @@ func TestMarkTaskAsDone(t *testing.T) {
 		// Try to mark as done again (update timestamp)
 		newCompletedTime := time.Now()
 		updateQuery = `UPDATE tasks SET done = TRUE, completed_at = ? WHERE id = ?`
-		_, err = database.DB.Exec(updateQuery, newCompletedTime, taskID)
+		_, err = database.GetDB().Exec(updateQuery, newCompletedTime, taskID)
 		require.NoError(t, err)
 
 		// Verify task is still marked as done and timestamp was updated
 		var completedAt time.Time
 		query := `SELECT completed_at FROM tasks WHERE id = ?`
-		err = database.DB.QueryRow(query, taskID).Scan(&completedAt)
+		err = database.GetDB().QueryRow(query, taskID).Scan(&completedAt)
 		require.NoError(t, err)
 
 		// The timestamp should be updated
@@ -166,7 +172,7 @@ func TestDoneCommandIntegration(t *testing.T) {
 		var title, description string
 		var done bool
 
-		err := database.DB.QueryRow(query, taskID).Scan(&id, &title, &description, &done)
+		err := database.GetDB().QueryRow(query, taskID).Scan(&id, &title, &description, &done)
 		require.NoError(t, err)
 		assert.Equal(t, taskID, id)
 		assert.False(t, done)
@@ -174,11 +180,11 @@ func TestDoneCommandIntegration(t *testing.T) {
 		// Step 2: Mark as done
 		completedTime := time.Now()
 		updateQuery := `UPDATE tasks SET done = TRUE, completed_at = ? WHERE id = ?`
-		_, err = database.DB.Exec(updateQuery, completedTime, taskID)
+		_, err = database.GetDB().Exec(updateQuery, completedTime, taskID)
 		require.NoError(t, err)
 
 		// Step 3: Verify the task is now completed
-		err = database.DB.QueryRow(query, taskID).Scan(&id, &title, &description, &done)
+		err = database.GetDB().QueryRow(query, taskID).Scan(&id, &title, &description, &done)
 		require.NoError(t, err)
 		assert.True(t, done)
 	})
@@ -189,7 +195,7 @@ func TestDoneCommandIntegration(t *testing.T) {
 		// Try to find a non-existent task
 		query := `SELECT id FROM tasks WHERE id = ?`
 		var id int
-		err := database.DB.QueryRow(query, 999).Scan(&id)
+		err := database.GetDB().QueryRow(query, 999).Scan(&id)
 		assert.Error(t, err) // Should be sql.ErrNoRows
 
 		// No tasks should exist in database
@@ -217,7 +223,7 @@ func TestMultipleTaskOperations(t *testing.T) {
 	completedTime := time.Now()
 	for i := 0; i < len(taskIDs); i += 2 {
 		updateQuery := `UPDATE tasks SET done = TRUE, completed_at = ? WHERE id = ?`
-		_, err := database.DB.Exec(updateQuery, completedTime, taskIDs[i])
+		_, err := database.GetDB().Exec(updateQuery, completedTime, taskIDs[i])
 		require.NoError(t, err)
 	}
 
@@ -245,11 +251,665 @@ func TestDoneWithDatabaseError(t *testing.T) {
 	taskID := insertTestTask(t, "Test Task", "Test Description", false)
 
 	// Close the database to simulate an error
-	database.DB.Close()
+	database.GetDB().Close()
 
 	// Try to find task - should get an error
 	query := `SELECT id FROM tasks WHERE id = ?`
 	var id int
-	err := database.DB.QueryRow(query, taskID).Scan(&id)
+	err := database.GetDB().QueryRow(query, taskID).Scan(&id)
 	assert.Error(t, err)
 }
+
+func TestDoneTaskNotFound(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+	taskID := insertTestTask(t, "Only task", "", false)
+
+	t.Run("unknown id is reported as not found", func(t *testing.T) {
+		output := runCommand(t, "done", "99999")
+		assert.Contains(t, output, "❌ Task not found: 99999")
+		assert.NotContains(t, output, "Error finding task")
+		assert.False(t, getTaskByID(t, taskID).Done)
+	})
+
+	t.Run("unknown uuid is reported as not found", func(t *testing.T) {
+		output := runCommand(t, "done", "00000000-0000-4000-8000-000000000000")
+		assert.Contains(t, output, "❌ Task not found: 00000000-0000-4000-8000-000000000000")
+	})
+
+	t.Run("database errors are not mistaken for a missing task", func(t *testing.T) {
+		_, err := database.GetDB().Exec(`DROP TABLE tasks`)
+		require.NoError(t, err)
+
+		output := runCommand(t, "done", fmt.Sprint(taskID))
+		assert.Contains(t, output, "Error finding task: no such table: tasks")
+		assert.NotContains(t, output, "Task not found")
+	})
+}
+
+func TestDoneZeroCompletedAt(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+
+	// A done task whose completion time was stored as the zero value
+	taskID := insertTestTask(t, "Zero Time Task", "Description", true)
+	_, err := database.GetDB().Exec(`UPDATE tasks SET completed_at = ? WHERE id = ?`, time.Time{}, taskID)
+	require.NoError(t, err)
+
+	output := runCommand(t, "done", fmt.Sprint(taskID))
+
+	assert.Contains(t, output, "Task already done!")
+	assert.Contains(t, output, "Completed At: N/A")
+	assert.NotContains(t, output, "0001-01-01")
+}
+
+func TestDoneWithNote(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	t.Run("completing with a note stores it", func(t *testing.T) {
+		clearTestTasks(t)
+		taskID := insertTestTask(t, "Fix bug", "Crash on startup", false)
+
+		output := runCommand(t, "done", fmt.Sprint(taskID), "--note", "Patched the nil check")
+		assert.Contains(t, output, "Task marked as done")
+
+		var note sql.NullString
+		err := database.GetDB().QueryRow(`SELECT completion_note FROM tasks WHERE id = ?`, taskID).Scan(&note)
+		require.NoError(t, err)
+		assert.True(t, note.Valid)
+		assert.Equal(t, "Patched the nil check", note.String)
+	})
+
+	t.Run("completing without a note leaves it NULL", func(t *testing.T) {
+		clearTestTasks(t)
+		taskID := insertTestTask(t, "Water plants", "", false)
+
+		runCommand(t, "done", fmt.Sprint(taskID))
+
+		var note sql.NullString
+		err := database.GetDB().QueryRow(`SELECT completion_note FROM tasks WHERE id = ?`, taskID).Scan(&note)
+		require.NoError(t, err)
+		assert.False(t, note.Valid)
+
+		output := runCommand(t, "show", fmt.Sprint(taskID))
+		assert.NotContains(t, output, "Note:")
+	})
+
+	t.Run("note appears in show", func(t *testing.T) {
+		clearTestTasks(t)
+		taskID := insertTestTask(t, "Send invoice", "", false)
+
+		runCommand(t, "done", fmt.Sprint(taskID), "-n", "Sent by email")
+
+		output := runCommand(t, "show", fmt.Sprint(taskID))
+		assert.Contains(t, output, "Title: Send invoice")
+		assert.Contains(t, output, "Note: Sent by email")
+	})
+
+	t.Run("note read from stdin keeps its lines", func(t *testing.T) {
+		clearTestTasks(t)
+		taskID := insertTestTask(t, "Release v2", "", false)
+
+		cmd.SetIn(strings.NewReader("Tagged v2.0.0\nRelease notes published\n\n"))
+		defer cmd.SetIn(nil)
+
+		output := runCommand(t, "done", fmt.Sprint(taskID), "--note", "-")
+		assert.Contains(t, output, "Task marked as done")
+
+		var note sql.NullString
+		err := database.GetDB().QueryRow(`SELECT completion_note FROM tasks WHERE id = ?`, taskID).Scan(&note)
+		require.NoError(t, err)
+		assert.Equal(t, "Tagged v2.0.0\nRelease notes published", note.String)
+
+		output = runCommand(t, "show", fmt.Sprint(taskID))
+		assert.Contains(t, output, "Note: Tagged v2.0.0\nRelease notes published")
+	})
+
+	t.Run("note from stdin is refused with interactive selection", func(t *testing.T) {
+		clearTestTasks(t)
+		taskID := insertTestTask(t, "Pick me", "", false)
+
+		output := runCommand(t, "done", "--interactive", "--note", "-")
+		assert.Contains(t, output, "Error: --note - reads the note from stdin")
+
+		task := getTaskByID(t, taskID)
+		assert.False(t, task.Done)
+	})
+}
+
+func TestDoneByFilter(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	setup := func(t *testing.T) (int, int, int) {
+		clearTestTasks(t)
+		first := insertTestTask(t, "[release] tag version", "", false)
+		second := insertTestTask(t, "[release] publish notes", "", false)
+		other := insertTestTask(t, "Plan next sprint", "", false)
+		return first, second, other
+	}
+
+	t.Run("completes every matching task with --yes", func(t *testing.T) {
+		first, second, other := setup(t)
+
+		output := runCommand(t, "done", "--title-prefix", "[release]", "--yes", "--note", "Released")
+
+		assert.Contains(t, output, "2 task(s) marked as done")
+		assert.True(t, getTaskByID(t, first).Done)
+		assert.True(t, getTaskByID(t, second).Done)
+		assert.False(t, getTaskByID(t, other).Done)
+
+		var note string
+		err := database.GetDB().QueryRow(`SELECT completion_note FROM tasks WHERE id = ?`, first).Scan(&note)
+		require.NoError(t, err)
+		assert.Equal(t, "Released", note)
+	})
+
+	t.Run("previews without --yes", func(t *testing.T) {
+		first, second, _ := setup(t)
+		previous := cmd.SetInputTerminal(func(io.Reader) bool { return true })
+		defer cmd.SetInputTerminal(previous)
+		cmd.SetIn(strings.NewReader(""))
+		defer cmd.SetIn(nil)
+
+		output := runCommand(t, "done", "--title-prefix", "[release]")
+
+		assert.Contains(t, output, "2 pending task(s) match the filters")
+		assert.Contains(t, output, "--yes")
+		assert.False(t, getTaskByID(t, first).Done)
+		assert.False(t, getTaskByID(t, second).Done)
+	})
+
+	t.Run("already completed tasks are left alone", func(t *testing.T) {
+		clearTestTasks(t)
+		completedTime := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+		doneID := insertTestTaskWithSpecificTime(t, "[release] old", "", true, completedTime, &completedTime)
+
+		output := runCommand(t, "done", "--title-prefix", "[release]", "--yes")
+		assert.Contains(t, output, "No pending tasks match the filters")
+
+		var completedAt time.Time
+		err := database.GetDB().QueryRow(`SELECT completed_at FROM tasks WHERE id = ?`, doneID).Scan(&completedAt)
+		require.NoError(t, err)
+		assert.True(t, completedTime.Equal(completedAt))
+	})
+
+	t.Run("quiet if none matches", func(t *testing.T) {
+		setup(t)
+
+		output := runCommand(t, "done", "--title-prefix", "[nothing]", "--yes", "--quiet-if-none")
+		assert.Empty(t, output)
+		assert.Equal(t, 0, countDoneTasks(t))
+	})
+
+	t.Run("quiet if none still reports completions", func(t *testing.T) {
+		first, second, _ := setup(t)
+
+		output := runCommand(t, "done", "--title-prefix", "[release]", "--yes", "--quiet-if-none")
+		assert.Contains(t, output, "2 task(s) marked as done")
+		assert.True(t, getTaskByID(t, first).Done)
+		assert.True(t, getTaskByID(t, second).Done)
+	})
+
+	t.Run("requires an id or a filter", func(t *testing.T) {
+		setup(t)
+
+		output := runCommand(t, "done")
+		assert.Contains(t, output, "provide a task id or at least one filter flag")
+		assert.Equal(t, 0, countDoneTasks(t))
+	})
+
+	t.Run("completes tasks carrying a tag", func(t *testing.T) {
+		clearTestTasks(t)
+		runCommand(t, "add", "Fix login bug", "--tag", "x,urgent")
+		runCommand(t, "add", "Write report", "--tag", "x", "--priority", "high")
+		runCommand(t, "add", "Call plumber", "--tag", "home")
+
+		output := runCommand(t, "done", "--tag", "x", "--yes")
+		assert.Contains(t, output, "2 task(s) marked as done")
+		assert.Equal(t, "Call plumber\n", pendingTitles(t))
+	})
+
+	t.Run("shares the tag, priority and status filters of list", func(t *testing.T) {
+		clearTestTasks(t)
+		runCommand(t, "add", "Fix login bug", "--tag", "work,urgent", "--priority", "high")
+		runCommand(t, "add", "Write report", "--tag", "work", "--priority", "high")
+		runCommand(t, "add", "Plan sprint", "--tag", "work", "--priority", "low")
+
+		output := runCommand(t, "done", "--tag", "work,urgent", "--tag-all", "--yes")
+		assert.Contains(t, output, "1 task(s) marked as done")
+		output = runCommand(t, "done", "--priority", "high", "--status", "pending", "--yes")
+		assert.Contains(t, output, "1 task(s) marked as done")
+		assert.Equal(t, "Plan sprint\n", pendingTitles(t))
+
+		output = runCommand(t, "done", "--status", "done", "--yes")
+		assert.Contains(t, output, "--status done matches none")
+		output = runCommand(t, "done", "--tag-all", "--yes")
+		assert.Contains(t, output, "Error: --tag-all requires --tag")
+		assert.Equal(t, "Plan sprint\n", pendingTitles(t))
+	})
+
+	t.Run("rejects an id combined with filters", func(t *testing.T) {
+		first, _, _ := setup(t)
+
+		output := runCommand(t, "done", fmt.Sprint(first), "--title-prefix", "[release]")
+		assert.Contains(t, output, "not both")
+		assert.Equal(t, 0, countDoneTasks(t))
+	})
+}
+
+// pendingTitles returns the titles of the pending tasks, one per line in id order
+func pendingTitles(t *testing.T) string {
+	rows, err := database.GetDB().Query("SELECT title FROM tasks WHERE done = FALSE ORDER BY id")
+	require.NoError(t, err)
+	defer rows.Close()
+
+	var titles strings.Builder
+	for rows.Next() {
+		var title string
+		require.NoError(t, rows.Scan(&title))
+		titles.WriteString(title + "\n")
+	}
+	require.NoError(t, rows.Err())
+	return titles.String()
+}
+
+// countDoneTasks returns the number of completed tasks in the database
+func countDoneTasks(t *testing.T) int {
+	var count int
+	err := database.GetDB().QueryRow("SELECT COUNT(*) FROM tasks WHERE done = TRUE").Scan(&count)
+	require.NoError(t, err)
+	return count
+}
+
+func TestDoneOverdueFilter(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+	yesterday := time.Now().Add(-24 * time.Hour)
+	tomorrow := time.Now().Add(24 * time.Hour)
+	overdueID := insertTestTaskWithDueDate(t, "Pay rent", false, &yesterday)
+	upcomingID := insertTestTaskWithDueDate(t, "Renew passport", false, &tomorrow)
+	undatedID := insertTestTaskWithDueDate(t, "Read a book", false, nil)
+
+	output := runCommand(t, "done", "--overdue", "--yes")
+
+	assert.Contains(t, output, "1 task(s) marked as done")
+	assert.True(t, getTaskByID(t, overdueID).Done)
+	assert.False(t, getTaskByID(t, upcomingID).Done)
+	assert.False(t, getTaskByID(t, undatedID).Done)
+}
+
+func TestDoneConfirmation(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+	defer cmd.SetIn(nil)
+
+	setup := func(t *testing.T) int {
+		clearTestTasks(t)
+		yesterday := time.Now().Add(-24 * time.Hour)
+		return insertTestTaskWithDueDate(t, "Pay rent", false, &yesterday)
+	}
+
+	t.Run("piped stdin without --yes fails and completes nothing", func(t *testing.T) {
+		overdueID := setup(t)
+		cmd.SetIn(strings.NewReader("y\n"))
+
+		output, err := runCommandErr(t, "done", "--overdue")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no tasks marked as done: input is not a terminal, pass --yes to confirm")
+		assert.Contains(t, output, "1 pending task(s) match the filters")
+		assert.False(t, getTaskByID(t, overdueID).Done)
+	})
+
+	t.Run("answer on a terminal completes the tasks", func(t *testing.T) {
+		overdueID := setup(t)
+		previous := cmd.SetInputTerminal(func(io.Reader) bool { return true })
+		defer cmd.SetInputTerminal(previous)
+		cmd.SetIn(strings.NewReader("y\n"))
+
+		output := runCommand(t, "done", "--overdue")
+		assert.Contains(t, output, "Mark them as done? [y/N]: ")
+		assert.Contains(t, output, "1 task(s) marked as done")
+		assert.True(t, getTaskByID(t, overdueID).Done)
+	})
+
+	t.Run("declining on a terminal is not an error", func(t *testing.T) {
+		overdueID := setup(t)
+		previous := cmd.SetInputTerminal(func(io.Reader) bool { return true })
+		defer cmd.SetInputTerminal(previous)
+		cmd.SetIn(strings.NewReader("n\n"))
+
+		output := runCommand(t, "done", "--overdue")
+		assert.Contains(t, output, "Aborted, no tasks marked as done")
+		assert.False(t, getTaskByID(t, overdueID).Done)
+	})
+}
+
+func TestDoneCompletedConcurrently(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+	taskID := insertTestTask(t, "Pay rent", "", false)
+	storedTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	// Complete the task from "another process" between the time done reads it
+	// and the time it writes the completion
+	_, err := database.GetDB().Exec(`CREATE TRIGGER complete_first BEFORE UPDATE ON tasks
+		WHEN OLD.done = FALSE AND NEW.done = TRUE
+		BEGIN
+			UPDATE tasks SET done = TRUE, completed_at = '2024-01-15 10:00:00' WHERE id = OLD.id;
+			SELECT RAISE(IGNORE);
+		END`)
+	require.NoError(t, err)
+	defer database.GetDB().Exec(`DROP TRIGGER IF EXISTS complete_first`)
+
+	output := runCommand(t, "done", fmt.Sprint(taskID))
+	assert.Contains(t, output, "Task already done!")
+	assert.NotContains(t, output, "Task marked as done")
+	assert.Contains(t, output, "Completed At: 2024-01-15 10:00:00")
+
+	var completedAt time.Time
+	require.NoError(t, database.GetDB().QueryRow(`SELECT completed_at FROM tasks WHERE id = ?`, taskID).Scan(&completedAt))
+	assert.True(t, storedTime.Equal(completedAt), "the stored completion time is kept, got %v", completedAt)
+}
+
+func TestDoneIdempotent(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	completedAtOf := func(t *testing.T, id int) time.Time {
+		var completedAt time.Time
+		err := database.GetDB().QueryRow(`SELECT completed_at FROM tasks WHERE id = ?`, id).Scan(&completedAt)
+		require.NoError(t, err)
+		return completedAt
+	}
+
+	t.Run("second done keeps the original completion time", func(t *testing.T) {
+		clearTestTasks(t)
+		originalTime := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+		taskID := insertTestTaskWithSpecificTime(t, "Finished task", "", true, originalTime, &originalTime)
+
+		output := runCommand(t, "done", fmt.Sprint(taskID))
+
+		assert.Contains(t, output, "Task already done!")
+		assert.True(t, originalTime.Equal(completedAtOf(t, taskID)))
+	})
+
+	t.Run("done then done again", func(t *testing.T) {
+		clearTestTasks(t)
+		taskID := insertTestTask(t, "Fresh task", "", false)
+
+		runCommand(t, "done", fmt.Sprint(taskID))
+		first := completedAtOf(t, taskID)
+
+		time.Sleep(10 * time.Millisecond)
+		runCommand(t, "done", fmt.Sprint(taskID))
+		assert.True(t, first.Equal(completedAtOf(t, taskID)))
+	})
+
+	t.Run("force re-stamps the completion time", func(t *testing.T) {
+		clearTestTasks(t)
+		originalTime := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+		taskID := insertTestTaskWithSpecificTime(t, "Finished task", "", true, originalTime, &originalTime)
+		_, err := database.GetDB().Exec(`UPDATE tasks SET completion_note = 'Original note' WHERE id = ?`, taskID)
+		require.NoError(t, err)
+
+		output := runCommand(t, "done", fmt.Sprint(taskID), "--force")
+
+		assert.Contains(t, output, "Task marked as done")
+		assert.WithinDuration(t, time.Now(), completedAtOf(t, taskID), 5*time.Second)
+		assert.Contains(t, runCommand(t, "show", fmt.Sprint(taskID)), "Note: Original note")
+	})
+}
+
+func TestDoneFromFile(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	writeIDs := func(t *testing.T, content string) string {
+		path := filepath.Join(t.TempDir(), "ids.txt")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+
+	t.Run("mixed valid and invalid ids", func(t *testing.T) {
+		clearTestTasks(t)
+		first := insertTestTask(t, "Write report", "", false)
+		second := insertTestTask(t, "Review PR", "", false)
+		completed := insertTestTask(t, "Already done", "", true)
+		other := insertTestTask(t, "Untouched", "", false)
+
+		path := writeIDs(t, fmt.Sprintf("%d\n\n  %d  \nabc\n-4\n9999\n%d\n%d\n", first, second, completed, first))
+		output := runCommand(t, "done", "--from-file", path)
+
+		assert.Contains(t, output, fmt.Sprintf("✓ Task marked as done: %d - Write report", first))
+		assert.Contains(t, output, fmt.Sprintf("✓ Task marked as done: %d - Review PR", second))
+		assert.Contains(t, output, "⚠️  Warning: invalid task id, skipping: abc")
+		assert.Contains(t, output, "⚠️  Warning: invalid task id, skipping: -4")
+		assert.Contains(t, output, "❌ Task not found: 9999")
+		assert.Contains(t, output, fmt.Sprintf("✅ Task already done: %d - Already done", completed))
+		assert.Contains(t, output, fmt.Sprintf("⚠️  Warning: duplicate task id, skipping: %d", first))
+		assert.Contains(t, output, "2 task(s) marked as done")
+
+		assert.True(t, getTaskByID(t, first).Done)
+		assert.True(t, getTaskByID(t, second).Done)
+		assert.False(t, getTaskByID(t, other).Done)
+	})
+
+	t.Run("empty file", func(t *testing.T) {
+		clearTestTasks(t)
+		insertTestTask(t, "Write report", "", false)
+
+		output := runCommand(t, "done", "--from-file", writeIDs(t, "\n  \n"))
+		assert.Contains(t, output, "No task ids found in file")
+		assert.Equal(t, 0, countDoneTasks(t))
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		output := runCommand(t, "done", "--from-file", filepath.Join(t.TempDir(), "missing.txt"))
+		assert.Contains(t, output, "Error reading ids")
+	})
+}
+
+func TestDoneInteractive(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+	defer cmd.SetIn(nil)
+
+	setup := func(t *testing.T) []int {
+		clearTestTasks(t)
+		return []int{
+			insertTestTask(t, "Write report", "", false),
+			insertTestTask(t, "Review PR", "", false),
+			insertTestTask(t, "Plan sprint", "", false),
+		}
+	}
+
+	t.Run("completes the selected tasks", func(t *testing.T) {
+		ids := setup(t)
+		insertTestTask(t, "Already done", "", true)
+
+		cmd.SetIn(strings.NewReader("1, 3\n"))
+		output := runCommand(t, "done", "--interactive", "--note", "Picked")
+
+		assert.Contains(t, output, "  1) Write report")
+		assert.Contains(t, output, "  3) Plan sprint")
+		assert.NotContains(t, output, "Already done (id")
+		assert.Contains(t, output, "2 task(s) marked as done")
+		assert.True(t, getTaskByID(t, ids[0]).Done)
+		assert.False(t, getTaskByID(t, ids[1]).Done)
+		assert.True(t, getTaskByID(t, ids[2]).Done)
+	})
+
+	t.Run("invalid selection completes nothing", func(t *testing.T) {
+		setup(t)
+
+		cmd.SetIn(strings.NewReader("2,7\n"))
+		output := runCommand(t, "done", "-i")
+
+		assert.Contains(t, output, `invalid selection "7"`)
+		assert.Equal(t, 0, countDoneTasks(t))
+	})
+
+	t.Run("empty selection", func(t *testing.T) {
+		setup(t)
+
+		cmd.SetIn(strings.NewReader(""))
+		output := runCommand(t, "done", "-i")
+
+		assert.Contains(t, output, "No tasks selected")
+		assert.Equal(t, 0, countDoneTasks(t))
+	})
+}
+
+func TestDoneByPosition(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	setup := func(t *testing.T) []int {
+		clearTestTasks(t)
+		ids := []int{
+			insertTestTask(t, "Banana", "", false),
+			insertTestTask(t, "Apple", "", false),
+			insertTestTask(t, "Cherry", "", false),
+		}
+		runCommand(t, "list", "--sort", "title")
+		return ids
+	}
+
+	t.Run("completes the task at the position", func(t *testing.T) {
+		ids := setup(t)
+
+		output := runCommand(t, "done", "#2")
+		assert.Contains(t, output, "✓ Task marked as done: Banana")
+		assert.True(t, getTaskByID(t, ids[0]).Done)
+		assert.False(t, getTaskByID(t, ids[1]).Done)
+
+		// Completing doesn't shift the positions of the last list
+		output = runCommand(t, "done", "#3")
+		assert.Contains(t, output, "✓ Task marked as done: Cherry")
+		assert.True(t, getTaskByID(t, ids[2]).Done)
+	})
+
+	t.Run("numbered list shows the positions", func(t *testing.T) {
+		ids := setup(t)
+		output := runCommand(t, "list", "--sort", "title", "--numbered")
+		assert.Contains(t, output, fmt.Sprintf("#1 ❌ %d - Apple\n", ids[1]))
+		assert.Contains(t, output, fmt.Sprintf("#3 ❌ %d - Cherry\n", ids[2]))
+	})
+
+	t.Run("stale list is refused", func(t *testing.T) {
+		ids := setup(t)
+		insertTestTask(t, "Added later", "", false)
+
+		output := runCommand(t, "done", "#1")
+		assert.Contains(t, output, "tasks were added or removed since the last list")
+		assert.False(t, getTaskByID(t, ids[1]).Done)
+	})
+
+	t.Run("invalid positions", func(t *testing.T) {
+		setup(t)
+		assert.Contains(t, runCommand(t, "done", "#4"), "position #4 is out of range, the last list showed 3 task(s)")
+		assert.Contains(t, runCommand(t, "done", "#x"), `invalid position "#x"`)
+		assert.Contains(t, runCommand(t, "done", "#0"), `invalid position "#0"`)
+	})
+
+	t.Run("requires a list first", func(t *testing.T) {
+		clearTestTasks(t)
+		_, err := database.GetDB().Exec(`DELETE FROM app_state`)
+		require.NoError(t, err)
+		insertTestTask(t, "Never listed", "", false)
+
+		assert.Contains(t, runCommand(t, "done", "#1"), "run tasker list first")
+	})
+}
+
+func TestDoneAtPosition(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	setup := func(t *testing.T) []int {
+		clearTestTasks(t)
+		ids := []int{
+			insertTestTask(t, "Banana", "", false),
+			insertTestTask(t, "Apple", "", false),
+			insertTestTask(t, "Cherry", "", false),
+		}
+		runCommand(t, "list", "--sort", "title")
+		return ids
+	}
+
+	t.Run("completes the task when the list is unchanged", func(t *testing.T) {
+		ids := setup(t)
+
+		output := runCommand(t, "done", "--at-position", "2")
+		assert.Contains(t, output, "✓ Task marked as done: Banana")
+		assert.True(t, getTaskByID(t, ids[0]).Done)
+		assert.False(t, getTaskByID(t, ids[1]).Done)
+	})
+
+	t.Run("refuses after a listed task was renamed", func(t *testing.T) {
+		ids := setup(t)
+		// The rename moves Banana to the end of a list sorted by title
+		runCommand(t, "rename", fmt.Sprint(ids[0]), "Zucchini")
+
+		output := runCommand(t, "done", "--at-position", "2")
+		assert.Contains(t, output, "Error: the listed tasks changed since the last list, run tasker list again")
+		for _, id := range ids {
+			assert.False(t, getTaskByID(t, id).Done)
+		}
+
+		// #2 only checks for added or removed tasks
+		output = runCommand(t, "done", "#2")
+		assert.Contains(t, output, "✓ Task marked as done: Zucchini")
+	})
+
+	t.Run("refuses after a listed task was completed", func(t *testing.T) {
+		ids := setup(t)
+		runCommand(t, "done", fmt.Sprint(ids[1]))
+
+		output := runCommand(t, "done", "--at-position", "3")
+		assert.Contains(t, output, "run tasker list again")
+		assert.False(t, getTaskByID(t, ids[2]).Done)
+
+		runCommand(t, "list", "--sort", "title")
+		output = runCommand(t, "done", "--at-position", "3")
+		assert.Contains(t, output, "✓ Task marked as done: Cherry")
+	})
+
+	t.Run("refuses after tasks were added", func(t *testing.T) {
+		ids := setup(t)
+		insertTestTask(t, "Added later", "", false)
+
+		output := runCommand(t, "done", "--at-position", "1")
+		assert.Contains(t, output, "tasks were added or removed since the last list")
+		assert.False(t, getTaskByID(t, ids[1]).Done)
+	})
+
+	t.Run("cannot be combined with a task id", func(t *testing.T) {
+		ids := setup(t)
+		output := runCommand(t, "done", fmt.Sprint(ids[0]), "--at-position", "1")
+		assert.Contains(t, output, "Error: --at-position cannot be combined with a task id")
+		assert.False(t, getTaskByID(t, ids[0]).Done)
+	})
+}
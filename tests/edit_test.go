@@ -0,0 +1,104 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEditTask(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	t.Run("diff shows only the changed title", func(t *testing.T) {
+		clearTestTasks(t)
+		taskID := insertTestTask(t, "Buy milk", "From the corner shop", false)
+
+		output := runCommand(t, "edit", fmt.Sprint(taskID), "--title", "Buy oat milk")
+
+		assert.Contains(t, output, "✓ Task updated: Buy oat milk")
+		assert.Contains(t, output, `Title: "Buy milk" → "Buy oat milk"`)
+		assert.NotContains(t, output, "Description:")
+
+		task := getTaskByID(t, taskID)
+		require.NotNil(t, task)
+		assert.Equal(t, "Buy oat milk", task.Title)
+		assert.Equal(t, "From the corner shop", task.Description)
+	})
+
+	t.Run("unchanged values are not shown", func(t *testing.T) {
+		clearTestTasks(t)
+		taskID := insertTestTask(t, "Buy milk", "From the corner shop", false)
+
+		output := runCommand(t, "edit", fmt.Sprint(taskID), "--title", "Buy milk", "--description", "From the market")
+
+		assert.Contains(t, output, `Description: "From the corner shop" → "From the market"`)
+		assert.NotContains(t, output, "Title:")
+	})
+
+	t.Run("no changes", func(t *testing.T) {
+		clearTestTasks(t)
+		taskID := insertTestTask(t, "Buy milk", "", false)
+
+		output := runCommand(t, "edit", fmt.Sprint(taskID), "--title", "Buy milk")
+		assert.Contains(t, output, "Nothing to update")
+	})
+
+	t.Run("empty title is rejected", func(t *testing.T) {
+		clearTestTasks(t)
+		taskID := insertTestTask(t, "Buy milk", "", false)
+
+		output := runCommand(t, "edit", fmt.Sprint(taskID), "--title", "   ")
+		assert.Contains(t, output, "title cannot be empty")
+		assert.Equal(t, "Buy milk", getTaskByID(t, taskID).Title)
+	})
+
+	t.Run("missing task", func(t *testing.T) {
+		clearTestTasks(t)
+
+		output := runCommand(t, "edit", "999", "--title", "Anything")
+		assert.Contains(t, output, "Task not found: 999")
+	})
+}
+
+func TestRenameTask(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	t.Run("renames and prints before and after", func(t *testing.T) {
+		clearTestTasks(t)
+		taskID := insertTestTask(t, "Buy milk", "From the corner shop", false)
+
+		output := runCommand(t, "rename", fmt.Sprint(taskID), "  Buy oat milk  ")
+
+		assert.Contains(t, output, "✓ Task renamed: Buy oat milk")
+		assert.Contains(t, output, `Title: "Buy milk" → "Buy oat milk"`)
+
+		task := getTaskByID(t, taskID)
+		require.NotNil(t, task)
+		assert.Equal(t, "Buy oat milk", task.Title)
+		assert.Equal(t, "From the corner shop", task.Description)
+	})
+
+	t.Run("rejects an empty title", func(t *testing.T) {
+		clearTestTasks(t)
+		taskID := insertTestTask(t, "Buy milk", "", false)
+
+		output := runCommand(t, "rename", fmt.Sprint(taskID), "   ")
+
+		assert.Contains(t, output, "title cannot be empty")
+		assert.Equal(t, "Buy milk", getTaskByID(t, taskID).Title)
+	})
+
+	t.Run("missing id", func(t *testing.T) {
+		clearTestTasks(t)
+
+		output := runCommand(t, "rename", "9999", "New title")
+
+		assert.Contains(t, output, "❌ Task not found: 9999")
+	})
+}
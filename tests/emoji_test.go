@@ -0,0 +1,113 @@
+package tests
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// assertNoEmoji fails when output contains any of the emoji tasker prints
+func assertNoEmoji(t *testing.T, output string) {
+	t.Helper()
+	for _, emoji := range []string{"✅", "❌", "⚠️", "👌", "↩️", "💼"} {
+		assert.NotContains(t, output, emoji)
+	}
+}
+
+// assertASCII fails when output contains any character outside ASCII
+func assertASCII(t *testing.T, output string) {
+	t.Helper()
+	for _, r := range output {
+		if r > unicode.MaxASCII {
+			assert.Failf(t, "output is not plain ASCII", "found %q in %q", r, output)
+			return
+		}
+	}
+}
+
+func TestNoEmoji(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+	writeTestConfig(t, `{"tag_icons": {"work": "💼"}}`)
+	runCommand(t, "add", "Fix login bug", "--tag", "work", "--due", "2000-01-01")
+	runCommand(t, "add", "Write report", "--done")
+	ids := listedTaskIDs(t, runCommand(t, "list", "--sort", "id"))
+	require.Len(t, ids, 2)
+	pending, done := ids[0], ids[1]
+
+	t.Run("emoji are shown by default", func(t *testing.T) {
+		output := runCommand(t, "list", "--sort", "id")
+		assert.Contains(t, output, fmt.Sprintf("❌ %d - 💼 Fix login bug\n", pending))
+		assert.Contains(t, output, fmt.Sprintf("✅ %d - Write report\n", done))
+	})
+
+	t.Run("list prints plain text", func(t *testing.T) {
+		output := runCommand(t, "list", "--sort", "id", "--no-emoji")
+		assertNoEmoji(t, output)
+		assert.Contains(t, output, fmt.Sprintf("[ ] %d - Fix login bug\n", pending))
+		assert.Contains(t, output, fmt.Sprintf("[x] %d - Write report\n", done))
+		assert.Contains(t, output, "Due: 2000-01-01 23:59 OVERDUE\n")
+	})
+
+	t.Run("messages of other commands print plain text", func(t *testing.T) {
+		output := runCommand(t, "done", "99999", "--no-emoji")
+		assert.Equal(t, "x Task not found: 99999\n", output)
+
+		output = runCommand(t, "done", fmt.Sprint(done), "--no-emoji")
+		assertNoEmoji(t, output)
+		assert.Contains(t, output, "[x] Task already done!")
+
+		output = runCommand(t, "add", "Fix login bug", "--no-emoji")
+		assertNoEmoji(t, output)
+		assert.Contains(t, output, "! Warning: ")
+
+		output = runCommand(t, "undone", fmt.Sprint(pending), "--no-emoji")
+		assert.Equal(t, fmt.Sprintf("- Task %d is not done yet, nothing to reopen\n", pending), output)
+	})
+
+	t.Run("environment variable turns emoji off", func(t *testing.T) {
+		t.Setenv("TASKER_NO_EMOJI", "1")
+		output := runCommand(t, "list", "--sort", "id")
+		assertNoEmoji(t, output)
+		assert.Contains(t, output, fmt.Sprintf("[ ] %d - Fix login bug\n", pending))
+
+		output = runCommand(t, "show", "99999")
+		assert.True(t, strings.HasPrefix(output, "x Task not found"), output)
+	})
+}
+
+func TestNoEmojiPlainASCII(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+	output := runCommand(t, "add", "Water plants", "--no-emoji")
+	assertASCII(t, output)
+	assert.Contains(t, output, "OK Task added: Water plants")
+
+	ids := listedTaskIDs(t, runCommand(t, "list", "--sort", "id"))
+	require.Len(t, ids, 1)
+	output = runCommand(t, "done", fmt.Sprint(ids[0]), "--no-emoji")
+	assertASCII(t, output)
+	assert.Contains(t, output, "OK Task marked as done: Water plants")
+
+	output = runCommand(t, "goal", "set", "1", "--per", "day", "--no-emoji")
+	assertASCII(t, output)
+	assert.Equal(t, "OK Goal set: 1 task(s) per day\n", output)
+
+	output = runCommand(t, "goal", "--no-emoji")
+	assertASCII(t, output)
+	assert.Contains(t, output, "OK goal reached")
+
+	output = runCommand(t, "edit", fmt.Sprint(ids[0]), "--title", "Water the plants", "--no-emoji")
+	assertASCII(t, output)
+	assert.Contains(t, output, `"Water plants" -> "Water the plants"`)
+}
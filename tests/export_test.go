@@ -1,15 +1,22 @@
 package tests
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"database/sql"
 	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/eduardamirelly/tasker/database"
+	"github.com/eduardamirelly/tasker/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -261,7 +268,7 @@ type testTaskData struct {
 func insertTestTaskWithTimestamp(t *testing.T, title, description string, done bool) int {
 	createdAt := time.Now()
 	query := `INSERT INTO tasks (title, description, done, created_at) VALUES (?, ?, ?, ?)`
-	result, err := database.DB.Exec(query, title, description, done, createdAt)
+	result, err := database.GetDB().Exec(query, title, description, done, createdAt)
 	require.NoError(t, err)
 
 	id, err := result.LastInsertId()
@@ -271,7 +278,7 @@ func insertTestTaskWithTimestamp(t *testing.T, title, description string, done b
 	if done {
 		completedAt := createdAt.Add(time.Hour) // Complete 1 hour after creation
 		updateQuery := `UPDATE tasks SET completed_at = ? WHERE id = ?`
-		_, err := database.DB.Exec(updateQuery, completedAt, id)
+		_, err := database.GetDB().Exec(updateQuery, completedAt, id)
 		require.NoError(t, err)
 	}
 
@@ -280,8 +287,8 @@ func insertTestTaskWithTimestamp(t *testing.T, title, description string, done b
 
 // insertTestTaskWithSpecificTime inserts a task with specific timestamps
 func insertTestTaskWithSpecificTime(t *testing.T, title, description string, done bool, createdAt time.Time, completedAt *time.Time) int {
-	query := `INSERT INTO tasks (title, description, done, created_at, completed_at) VALUES (?, ?, ?, ?, ?)`
-	result, err := database.DB.Exec(query, title, description, done, createdAt, completedAt)
+	query := `INSERT INTO tasks (uuid, title, description, done, created_at, completed_at) VALUES (?, ?, ?, ?, ?, ?)`
+	result, err := database.GetDB().Exec(query, models.NewUUID(), title, description, done, createdAt, completedAt)
 	require.NoError(t, err)
 
 	id, err := result.LastInsertId()
@@ -369,7 +376,7 @@ type exportTask struct {
 // getAllTasksForExport retrieves all tasks from the database for export testing
 func getAllTasksForExport() ([]exportTask, error) {
 	query := `SELECT id, title, description, done, created_at, completed_at FROM tasks`
-	rows, err := database.DB.Query(query)
+	rows, err := database.GetDB().Query(query)
 	if err != nil {
 		return nil, err
 	}
@@ -387,3 +394,789 @@ func getAllTasksForExport() ([]exportTask, error) {
 
 	return tasks, nil
 }
+
+func TestExportTemplate(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	createdAt := time.Date(2024, 1, 10, 9, 0, 0, 0, time.UTC)
+	completedAt := time.Date(2024, 1, 12, 18, 30, 0, 0, time.UTC)
+
+	t.Run("renders one line per task", func(t *testing.T) {
+		clearTestTasks(t)
+		firstID := insertTestTaskWithSpecificTime(t, "Write report", "Quarterly", true, createdAt, &completedAt)
+		secondID := insertTestTaskWithSpecificTime(t, "Buy groceries", "", false, createdAt, nil)
+
+		outputPath := filepath.Join(t.TempDir(), "tasks.txt")
+		template := `{{.ID}},{{.Title}},{{if .Done}}done{{else}}pending{{end}},{{dateFormat "2006-01-02" .CreatedAt}},{{date .CompletedAt}}`
+		output := runCommand(t, "export", "-o", outputPath, "--template", template)
+		assert.Contains(t, output, "Tasks exported successfully")
+
+		content, err := os.ReadFile(outputPath)
+		require.NoError(t, err)
+
+		expected := fmt.Sprintf("%d,Write report,done,2024-01-10,2024-01-12 18:30:00\n", firstID) +
+			fmt.Sprintf("%d,Buy groceries,pending,2024-01-10,\n", secondID)
+		assert.Equal(t, expected, string(content))
+	})
+
+	t.Run("invalid template is rejected before writing", func(t *testing.T) {
+		clearTestTasks(t)
+		insertTestTask(t, "Task", "", false)
+
+		outputPath := filepath.Join(t.TempDir(), "tasks.txt")
+		output := runCommand(t, "export", "-o", outputPath, "--template", "{{.Title")
+
+		assert.Contains(t, output, "invalid template")
+		_, err := os.Stat(outputPath)
+		assert.True(t, os.IsNotExist(err))
+	})
+}
+
+func TestExportSelectedIDs(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+	var ids []int
+	for i := 1; i <= 6; i++ {
+		ids = append(ids, insertTestTaskWithTimestamp(t, fmt.Sprintf("Task %d", i), "", false))
+	}
+
+	exportedIDs := func(t *testing.T, path string) []string {
+		records := readCSVFile(t, path)
+		require.NotEmpty(t, records)
+		var result []string
+		for _, record := range records[1:] {
+			result = append(result, record[0])
+		}
+		return result
+	}
+
+	t.Run("positional ids", func(t *testing.T) {
+		outputPath := filepath.Join(t.TempDir(), "tasks.csv")
+		runCommand(t, "export", strconv.Itoa(ids[2]), "-o", outputPath)
+
+		assert.Equal(t, []string{strconv.Itoa(ids[2])}, exportedIDs(t, outputPath))
+	})
+
+	t.Run("ids flag", func(t *testing.T) {
+		outputPath := filepath.Join(t.TempDir(), "tasks.csv")
+		idList := fmt.Sprintf("%d,%d,%d", ids[0], ids[2], ids[4])
+		runCommand(t, "export", "--ids", idList, "-o", outputPath)
+
+		expected := []string{strconv.Itoa(ids[0]), strconv.Itoa(ids[2]), strconv.Itoa(ids[4])}
+		assert.Equal(t, expected, exportedIDs(t, outputPath))
+	})
+
+	t.Run("id range", func(t *testing.T) {
+		outputPath := filepath.Join(t.TempDir(), "tasks.csv")
+		runCommand(t, "export", "--id-range", fmt.Sprintf("%d-%d", ids[1], ids[3]), "-o", outputPath)
+
+		expected := []string{strconv.Itoa(ids[1]), strconv.Itoa(ids[2]), strconv.Itoa(ids[3])}
+		assert.Equal(t, expected, exportedIDs(t, outputPath))
+	})
+
+	t.Run("missing ids are reported", func(t *testing.T) {
+		outputPath := filepath.Join(t.TempDir(), "tasks.csv")
+		output := runCommand(t, "export", "--ids", fmt.Sprintf("%d,9999", ids[0]), "-o", outputPath)
+
+		assert.Contains(t, output, "⚠️  Warning: task not found, skipping: 9999")
+		assert.Equal(t, []string{strconv.Itoa(ids[0])}, exportedIDs(t, outputPath))
+	})
+
+	t.Run("works with templates", func(t *testing.T) {
+		outputPath := filepath.Join(t.TempDir(), "tasks.txt")
+		runCommand(t, "export", strconv.Itoa(ids[5]), "--template", "{{.Title}}", "-o", outputPath)
+
+		content, err := os.ReadFile(outputPath)
+		require.NoError(t, err)
+		assert.Equal(t, "Task 6\n", string(content))
+	})
+
+	t.Run("invalid selections are rejected", func(t *testing.T) {
+		for _, args := range [][]string{
+			{"--id-range", "20-10"},
+			{"--id-range", "abc"},
+			{"abc"},
+			{"1", "--id-range", "1-3"},
+		} {
+			outputPath := filepath.Join(t.TempDir(), "tasks.csv")
+			output := runCommand(t, append([]string{"export", "-o", outputPath}, args...)...)
+
+			assert.Contains(t, output, "Error exporting tasks", "args: %v", args)
+			_, err := os.Stat(outputPath)
+			assert.True(t, os.IsNotExist(err), "args: %v", args)
+		}
+	})
+}
+
+func TestExportDefaultDirectory(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+	insertTestTaskWithTimestamp(t, "Exported task", "", false)
+
+	t.Run("default output lands in TASKER_EXPORT_DIR", func(t *testing.T) {
+		exportDir := filepath.Join(t.TempDir(), "exports", "nested")
+		t.Setenv("TASKER_EXPORT_DIR", exportDir)
+
+		output := runCommand(t, "export")
+
+		expectedPath := filepath.Join(exportDir, "tasks.csv")
+		assert.Contains(t, output, expectedPath)
+		records := readCSVFile(t, expectedPath)
+		require.Len(t, records, 2)
+		assert.Equal(t, "Exported task", records[1][1])
+	})
+
+	t.Run("explicit output wins", func(t *testing.T) {
+		t.Setenv("TASKER_EXPORT_DIR", filepath.Join(t.TempDir(), "unused"))
+		outputPath := filepath.Join(t.TempDir(), "explicit.csv")
+
+		runCommand(t, "export", "-o", outputPath)
+
+		_, err := os.Stat(outputPath)
+		assert.NoError(t, err)
+		_, err = os.Stat(os.Getenv("TASKER_EXPORT_DIR"))
+		assert.True(t, os.IsNotExist(err))
+	})
+}
+
+func TestExportBOM(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+	insertTestTaskWithTimestamp(t, "Unicode Task 🚀", "Emojis: 🎉🎯", false)
+
+	bom := []byte{0xEF, 0xBB, 0xBF}
+
+	t.Run("with --bom", func(t *testing.T) {
+		outputPath := filepath.Join(t.TempDir(), "tasks.csv")
+		runCommand(t, "export", "--bom", "-o", outputPath)
+
+		content, err := os.ReadFile(outputPath)
+		require.NoError(t, err)
+		require.True(t, bytes.HasPrefix(content, bom))
+
+		records, err := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(content, bom))).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+		assert.Equal(t, "ID", records[0][0])
+		assert.Equal(t, "Unicode Task 🚀", records[1][1])
+	})
+
+	t.Run("without --bom", func(t *testing.T) {
+		outputPath := filepath.Join(t.TempDir(), "tasks.csv")
+		runCommand(t, "export", "-o", outputPath)
+
+		content, err := os.ReadFile(outputPath)
+		require.NoError(t, err)
+		assert.False(t, bytes.HasPrefix(content, bom))
+		assert.True(t, bytes.HasPrefix(content, []byte("ID,")))
+	})
+}
+
+func TestExportJSONManifest(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+	first := insertTestTaskWithTimestamp(t, "First", "", false)
+	insertTestTaskWithTimestamp(t, "Second", "", true)
+	third := insertTestTaskWithTimestamp(t, "Third", "", false)
+
+	type manifest struct {
+		ExportedAt time.Time         `json:"exported_at"`
+		Version    string            `json:"version"`
+		Count      int               `json:"count"`
+		Filters    map[string]string `json:"filters"`
+		Tasks      []models.Task     `json:"tasks"`
+	}
+
+	t.Run("metadata reflects the export", func(t *testing.T) {
+		outputPath := filepath.Join(t.TempDir(), "backup.json")
+		before := time.Now().Add(-time.Second)
+		output := runCommand(t, "export", "--format", "json", "--manifest", "--ids", fmt.Sprintf("%d,%d", first, third), "-o", outputPath)
+		assert.Contains(t, output, "Tasks exported successfully")
+
+		content, err := os.ReadFile(outputPath)
+		require.NoError(t, err)
+
+		var got manifest
+		require.NoError(t, json.Unmarshal(content, &got))
+		assert.True(t, got.ExportedAt.After(before) && got.ExportedAt.Before(time.Now().Add(time.Second)))
+		assert.NotEmpty(t, got.Version)
+		assert.Equal(t, 2, got.Count)
+		assert.Len(t, got.Tasks, got.Count)
+		assert.Equal(t, map[string]string{"ids": fmt.Sprintf("%d,%d", first, third)}, got.Filters)
+		assert.Equal(t, "First", got.Tasks[0].Title)
+		assert.Equal(t, "Third", got.Tasks[1].Title)
+	})
+
+	t.Run("id range filter", func(t *testing.T) {
+		outputPath := filepath.Join(t.TempDir(), "backup.json")
+		runCommand(t, "export", "--format", "json", "--manifest", "--id-range", fmt.Sprintf("%d-%d", first, third), "-o", outputPath)
+
+		content, err := os.ReadFile(outputPath)
+		require.NoError(t, err)
+
+		var got manifest
+		require.NoError(t, json.Unmarshal(content, &got))
+		assert.Equal(t, 3, got.Count)
+		assert.Equal(t, fmt.Sprintf("%d-%d", first, third), got.Filters["id_range"])
+	})
+
+	t.Run("plain array without --manifest", func(t *testing.T) {
+		outputPath := filepath.Join(t.TempDir(), "tasks.json")
+		runCommand(t, "export", "--format", "json", "-o", outputPath)
+
+		content, err := os.ReadFile(outputPath)
+		require.NoError(t, err)
+
+		var tasks []models.Task
+		require.NoError(t, json.Unmarshal(content, &tasks))
+		assert.Len(t, tasks, 3)
+	})
+
+	t.Run("manifest requires json", func(t *testing.T) {
+		outputPath := filepath.Join(t.TempDir(), "tasks.csv")
+		output := runCommand(t, "export", "--manifest", "-o", outputPath)
+		assert.Contains(t, output, "--manifest requires --format json")
+		assert.NoFileExists(t, outputPath)
+	})
+}
+
+func TestExportJSONRoundTrip(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+	createdAt := time.Date(2024, 5, 1, 8, 15, 30, 0, time.UTC)
+	completedAt := time.Date(2024, 5, 3, 17, 45, 0, 0, time.UTC)
+	pendingID := insertTestTaskWithSpecificTime(t, "Write docs", "Usage, examples \"and\" FAQ", false, createdAt, nil)
+	doneID := insertTestTaskWithSpecificTime(t, "Ship release", "", true, createdAt, &completedAt)
+
+	outputPath := filepath.Join(t.TempDir(), "tasks.json")
+	output := runCommand(t, "export", "--format", "json", "--sort", "id", "-o", outputPath)
+	assert.Contains(t, output, "Tasks exported successfully to "+outputPath)
+
+	content, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+
+	var tasks []models.Task
+	require.NoError(t, json.Unmarshal(content, &tasks))
+	require.Len(t, tasks, 2)
+
+	pending, done := tasks[0], tasks[1]
+	assert.Equal(t, pendingID, pending.ID)
+	assert.Equal(t, "Write docs", pending.Title)
+	assert.Equal(t, "Usage, examples \"and\" FAQ", pending.Description)
+	assert.False(t, pending.Done)
+	assert.True(t, createdAt.Equal(pending.CreatedAt), "created at %v", pending.CreatedAt)
+	assert.Nil(t, pending.CompletedAt)
+
+	assert.Equal(t, doneID, done.ID)
+	assert.Equal(t, "Ship release", done.Title)
+	assert.True(t, done.Done)
+	assert.True(t, createdAt.Equal(done.CreatedAt), "created at %v", done.CreatedAt)
+	require.NotNil(t, done.CompletedAt)
+	assert.True(t, completedAt.Equal(*done.CompletedAt), "completed at %v", *done.CompletedAt)
+
+	// A pending task has no completion time, so the field is left out entirely
+	var raw []map[string]interface{}
+	require.NoError(t, json.Unmarshal(content, &raw))
+	assert.NotContains(t, raw[0], "completed_at")
+	assert.Contains(t, raw[1], "completed_at")
+}
+
+func TestExportRefusesDatabasePath(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+	insertTestTaskWithTimestamp(t, "Precious task", "", false)
+
+	dbPath, err := database.Path()
+	require.NoError(t, err)
+	require.NotEmpty(t, dbPath)
+
+	before, err := os.ReadFile(dbPath)
+	require.NoError(t, err)
+
+	aliases := map[string]string{
+		"exact path":   dbPath,
+		"unclean path": filepath.Join(filepath.Dir(dbPath), "sub", "..", filepath.Base(dbPath)),
+	}
+	symlink := filepath.Join(t.TempDir(), "link.db")
+	if os.Symlink(dbPath, symlink) == nil {
+		aliases["symlink"] = symlink
+	}
+
+	for name, path := range aliases {
+		t.Run(name, func(t *testing.T) {
+			output := runCommand(t, "export", "-o", path)
+			assert.Contains(t, output, "refusing to write to")
+			assert.Contains(t, output, "it is the tasker database")
+
+			after, err := os.ReadFile(dbPath)
+			require.NoError(t, err)
+			assert.Equal(t, before, after)
+			assert.Equal(t, 1, getTaskCount(t))
+		})
+	}
+
+	t.Run("other .db files are allowed", func(t *testing.T) {
+		outputPath := filepath.Join(t.TempDir(), "other.db")
+		output := runCommand(t, "export", "-o", outputPath)
+		assert.Contains(t, output, "Tasks exported successfully")
+	})
+}
+
+func TestExportMaxRows(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+	for i := 1; i <= 5; i++ {
+		insertTestTaskWithTimestamp(t, fmt.Sprintf("Task %d", i), "", false)
+	}
+
+	t.Run("errors above the cap", func(t *testing.T) {
+		outputPath := filepath.Join(t.TempDir(), "tasks.csv")
+		output := runCommand(t, "export", "--max-rows", "3", "-o", outputPath)
+
+		assert.Contains(t, output, "5 tasks match, more than --max-rows 3")
+		assert.NoFileExists(t, outputPath)
+	})
+
+	t.Run("truncates with --truncate", func(t *testing.T) {
+		outputPath := filepath.Join(t.TempDir(), "tasks.csv")
+		output := runCommand(t, "export", "--max-rows", "3", "--truncate", "-o", outputPath)
+
+		assert.Contains(t, output, "⚠️  Warning: exporting only the first 3 of 5 tasks")
+		records := readCSVFile(t, outputPath)
+		require.Len(t, records, 4)
+		assert.Equal(t, "Task 1", records[1][1])
+		assert.Equal(t, "Task 3", records[3][1])
+	})
+
+	t.Run("exports everything within the cap", func(t *testing.T) {
+		outputPath := filepath.Join(t.TempDir(), "tasks.csv")
+		output := runCommand(t, "export", "--max-rows", "5", "-o", outputPath)
+
+		assert.Contains(t, output, "Tasks exported successfully")
+		assert.Len(t, readCSVFile(t, outputPath), 6)
+	})
+}
+
+func TestExportAppend(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+	first := insertTestTaskWithTimestamp(t, "First", "", false)
+
+	outputPath := filepath.Join(t.TempDir(), "log.csv")
+	runCommand(t, "export", "--append", "--ids", fmt.Sprint(first), "-o", outputPath)
+
+	second := insertTestTaskWithTimestamp(t, "Second", "", false)
+	runCommand(t, "export", "--append", "--ids", fmt.Sprint(second), "-o", outputPath)
+
+	records := readCSVFile(t, outputPath)
+	require.Len(t, records, 3, "header plus one row per export")
+	assert.Equal(t, "ID", records[0][0])
+	assert.Equal(t, "First", records[1][1])
+	assert.Equal(t, "Second", records[2][1])
+
+	t.Run("empty existing file gets a header", func(t *testing.T) {
+		emptyPath := filepath.Join(t.TempDir(), "empty.csv")
+		require.NoError(t, os.WriteFile(emptyPath, nil, 0644))
+
+		runCommand(t, "export", "--append", "--ids", fmt.Sprint(first), "-o", emptyPath)
+		records := readCSVFile(t, emptyPath)
+		require.Len(t, records, 2)
+		assert.Equal(t, "ID", records[0][0])
+	})
+
+	t.Run("without --append the file is replaced", func(t *testing.T) {
+		runCommand(t, "export", "--ids", fmt.Sprint(second), "-o", outputPath)
+		assert.Len(t, readCSVFile(t, outputPath), 2)
+	})
+
+	t.Run("json cannot be appended", func(t *testing.T) {
+		output := runCommand(t, "export", "--append", "--format", "json", "-o", filepath.Join(t.TempDir(), "tasks.json"))
+		assert.Contains(t, output, "--append cannot be used with JSON")
+	})
+}
+
+func TestExportSQL(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+	runCommand(t, "add", "Call O'Brien", "-d", `Ask about the "quote"; then hang up`, "--tag", "work", "--priority", "high")
+	runCommand(t, "add", "Water plants", "--due", "2030-01-15 09:30")
+	doneID := insertTestTask(t, "Finished task", "", false)
+	runCommand(t, "done", fmt.Sprint(doneID), "--note", "It's done")
+	runCommand(t, "attach", fmt.Sprint(doneID), "https://example.com/o'brien?q=1;2", "--label", "Bob's notes")
+	runCommand(t, "attach", fmt.Sprint(doneID-2), "https://example.com/call")
+	runCommand(t, "attach", fmt.Sprint(doneID), "/tmp/report.pdf")
+
+	outputPath := filepath.Join(t.TempDir(), "tasks.sql")
+	output := runCommand(t, "export", "--format", "sql", "-o", outputPath)
+	assert.Contains(t, output, "Tasks exported successfully")
+
+	script, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(script), "CREATE TABLE IF NOT EXISTS tasks")
+	assert.Contains(t, string(script), "'Call O''Brien'")
+
+	// Rebuild a fresh database from the export
+	fresh, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	defer fresh.Close()
+	fresh.SetMaxOpenConns(1)
+	_, err = fresh.Exec(string(script))
+	require.NoError(t, err)
+
+	type row struct {
+		ID                 int
+		UUID, Title        string
+		Description        string
+		Done               bool
+		CreatedAt          time.Time
+		CompletedAt        *time.Time
+		CompletionNote     *string
+		DueDate, UpdatedAt *time.Time
+		Priority           string
+	}
+	load := func(db *sql.DB) []row {
+		rows, err := db.Query(`SELECT id, COALESCE(uuid, ''), title, description, done, created_at, completed_at, completion_note, due_date, updated_at, priority FROM tasks ORDER BY id`)
+		require.NoError(t, err)
+		defer rows.Close()
+
+		var loaded []row
+		for rows.Next() {
+			var r row
+			require.NoError(t, rows.Scan(&r.ID, &r.UUID, &r.Title, &r.Description, &r.Done, &r.CreatedAt, &r.CompletedAt, &r.CompletionNote, &r.DueDate, &r.UpdatedAt, &r.Priority))
+			loaded = append(loaded, r)
+		}
+		require.NoError(t, rows.Err())
+		return loaded
+	}
+
+	original, restored := load(database.GetDB()), load(fresh)
+	require.Len(t, restored, 3)
+	for i := range original {
+		assert.Equal(t, original[i].ID, restored[i].ID)
+		assert.Equal(t, original[i].UUID, restored[i].UUID)
+		assert.Equal(t, original[i].Title, restored[i].Title)
+		assert.Equal(t, original[i].Description, restored[i].Description)
+		assert.Equal(t, original[i].Done, restored[i].Done)
+		assert.True(t, original[i].CreatedAt.Equal(restored[i].CreatedAt), "created_at of %q", original[i].Title)
+		assert.Equal(t, original[i].CompletedAt == nil, restored[i].CompletedAt == nil)
+		assert.Equal(t, original[i].CompletionNote, restored[i].CompletionNote)
+		assert.Equal(t, original[i].DueDate == nil, restored[i].DueDate == nil)
+		assert.Equal(t, original[i].Priority, restored[i].Priority)
+	}
+	require.NotNil(t, restored[2].CompletedAt)
+	assert.True(t, original[2].CompletedAt.Equal(*restored[2].CompletedAt))
+
+	var tag string
+	require.NoError(t, fresh.QueryRow(`SELECT tag FROM task_tags WHERE task_id = ?`, original[0].ID).Scan(&tag))
+	assert.Equal(t, "work", tag)
+
+	loadAttachments := func(db *sql.DB, taskID int) [][2]string {
+		rows, err := db.Query(`SELECT label, uri FROM task_attachments WHERE task_id = ? ORDER BY rowid`, taskID)
+		require.NoError(t, err)
+		defer rows.Close()
+
+		var attachments [][2]string
+		for rows.Next() {
+			var attachment [2]string
+			require.NoError(t, rows.Scan(&attachment[0], &attachment[1]))
+			attachments = append(attachments, attachment)
+		}
+		require.NoError(t, rows.Err())
+		return attachments
+	}
+	assert.Equal(t, [][2]string{{"Bob's notes", "https://example.com/o'brien?q=1;2"}, {"", "/tmp/report.pdf"}}, loadAttachments(fresh, doneID))
+	assert.Equal(t, [][2]string{{"", "https://example.com/call"}}, loadAttachments(fresh, doneID-2))
+	assert.Equal(t, loadAttachments(database.GetDB(), doneID), loadAttachments(fresh, doneID))
+
+	t.Run("append is rejected", func(t *testing.T) {
+		output := runCommand(t, "export", "--format", "sql", "--append", "-o", outputPath)
+		assert.Contains(t, output, "--append cannot be used with SQL")
+	})
+}
+
+func TestExportSinceLastExport(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+	_, err := database.GetDB().Exec(`DELETE FROM app_state`)
+	require.NoError(t, err)
+
+	oldID := insertTestTask(t, "Old task", "", false)
+	oldDoneID := insertTestTask(t, "Old done task", "", true)
+	// Keep the existing tasks clearly before the first export
+	past := time.Now().Add(-time.Hour)
+	_, err = database.GetDB().Exec(`UPDATE tasks SET created_at = ?, updated_at = ?`, past, past)
+	require.NoError(t, err)
+
+	exportedTitles := func(t *testing.T, path string) []string {
+		records := readCSVFile(t, path)
+		var titles []string
+		for _, record := range records[1:] {
+			titles = append(titles, record[1])
+		}
+		return titles
+	}
+
+	dir := t.TempDir()
+	fullPath := filepath.Join(dir, "full.csv")
+	runCommand(t, "export", "--since-last-export", "-o", fullPath)
+	assert.ElementsMatch(t, []string{"Old task", "Old done task"}, exportedTitles(t, fullPath), "the first export includes everything")
+
+	firstMarker, ok, err := database.GetState("last_export_at")
+	require.NoError(t, err)
+	require.True(t, ok, "the export time is recorded")
+
+	runCommand(t, "add", "New task")
+	runCommand(t, "edit", fmt.Sprint(oldID), "--title", "Old task, edited")
+
+	incrementalPath := filepath.Join(dir, "incremental.csv")
+	runCommand(t, "export", "--since-last-export", "-o", incrementalPath)
+	assert.ElementsMatch(t, []string{"New task", "Old task, edited"}, exportedTitles(t, incrementalPath))
+
+	secondMarker, _, err := database.GetState("last_export_at")
+	require.NoError(t, err)
+	first, err := time.Parse(time.RFC3339Nano, firstMarker)
+	require.NoError(t, err)
+	second, err := time.Parse(time.RFC3339Nano, secondMarker)
+	require.NoError(t, err)
+	assert.True(t, second.After(first), "each export moves the marker")
+
+	t.Run("selected ids don't move the marker", func(t *testing.T) {
+		_, err := database.GetDB().Exec(`DELETE FROM app_state`)
+		require.NoError(t, err)
+		runCommand(t, "export", fmt.Sprint(oldDoneID), "-o", filepath.Join(dir, "one.csv"))
+
+		path := filepath.Join(dir, "after-ids.csv")
+		runCommand(t, "export", "--since-last-export", "-o", path)
+		assert.Len(t, exportedTitles(t, path), 3)
+	})
+
+	t.Run("ids are rejected", func(t *testing.T) {
+		output := runCommand(t, "export", "--since-last-export", "--ids", fmt.Sprint(oldID))
+		assert.Contains(t, output, "--since-last-export cannot be combined with task ids")
+	})
+}
+
+func TestExportSorted(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+	runCommand(t, "add", "banana", "--priority", "low")
+	runCommand(t, "add", "Cherry", "--priority", "high")
+	runCommand(t, "add", "apple")
+
+	exportTitles := func(t *testing.T, args ...string) []string {
+		path := filepath.Join(t.TempDir(), "tasks.csv")
+		runCommand(t, append([]string{"export", "-o", path}, args...)...)
+
+		var titles []string
+		for _, record := range readCSVFile(t, path)[1:] {
+			titles = append(titles, record[1])
+		}
+		return titles
+	}
+
+	assert.Equal(t, []string{"banana", "Cherry", "apple"}, exportTitles(t), "default order is oldest first")
+	assert.Equal(t, []string{"apple", "banana", "Cherry"}, exportTitles(t, "--sort", "title"))
+	assert.Equal(t, []string{"Cherry", "banana", "apple"}, exportTitles(t, "--sort", "title", "--reverse"))
+	assert.Equal(t, []string{"banana", "apple", "Cherry"}, exportTitles(t, "--sort", "priority"))
+
+	t.Run("json", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "tasks.json")
+		runCommand(t, "export", "--format", "json", "--sort", "title", "-o", path)
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		var tasks []models.Task
+		require.NoError(t, json.Unmarshal(data, &tasks))
+		require.Len(t, tasks, 3)
+		assert.Equal(t, "apple", tasks[0].Title)
+		assert.Equal(t, "Cherry", tasks[2].Title)
+	})
+
+	t.Run("invalid sort key", func(t *testing.T) {
+		output := runCommand(t, "export", "--sort", "color", "-o", filepath.Join(t.TempDir(), "tasks.csv"))
+		assert.Contains(t, output, `Error exporting tasks: invalid sort key "color"`)
+	})
+}
+
+func TestExportCRLF(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+	insertTestTask(t, "First task", "Line one", false)
+	insertTestTask(t, "Second task", "", true)
+
+	export := func(t *testing.T, args ...string) string {
+		path := filepath.Join(t.TempDir(), "tasks.csv")
+		runCommand(t, append([]string{"export", "-o", path}, args...)...)
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	t.Run("LF by default", func(t *testing.T) {
+		content := export(t)
+		assert.NotContains(t, content, "\r")
+		assert.Equal(t, 3, strings.Count(content, "\n"))
+	})
+
+	t.Run("CRLF with --crlf", func(t *testing.T) {
+		content := export(t, "--crlf")
+		assert.Equal(t, 3, strings.Count(content, "\r\n"))
+		assert.Equal(t, 3, strings.Count(content, "\n"), "every line ends with CRLF")
+		assert.True(t, strings.HasPrefix(content, "ID,Title,Description,Done,Created At,Completed At,UUID\r\n"))
+	})
+
+	t.Run("only for CSV", func(t *testing.T) {
+		output := runCommand(t, "export", "--format", "json", "--crlf", "-o", filepath.Join(t.TempDir(), "tasks.json"))
+		assert.Contains(t, output, "--crlf only apply to CSV exports")
+	})
+}
+
+func TestExportChecksum(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+	insertTestTask(t, "First task", "Line one", false)
+	insertTestTask(t, "Second task", "", true)
+
+	dir := t.TempDir()
+	outputPath := filepath.Join(dir, "backup.csv")
+
+	t.Run("export writes a sha256sum sidecar", func(t *testing.T) {
+		runCommand(t, "export", "-o", outputPath, "--checksum")
+
+		data, err := os.ReadFile(outputPath)
+		require.NoError(t, err)
+		sum := sha256.Sum256(data)
+
+		sidecar, err := os.ReadFile(outputPath + ".sha256")
+		require.NoError(t, err)
+		assert.Equal(t, hex.EncodeToString(sum[:])+"  backup.csv\n", string(sidecar))
+	})
+
+	t.Run("verification succeeds on the untouched file", func(t *testing.T) {
+		output, err := runCommandErr(t, "verify-export", outputPath)
+		require.NoError(t, err)
+		assert.Contains(t, output, "✓ "+outputPath+" matches its checksum")
+	})
+
+	t.Run("verification fails after tampering", func(t *testing.T) {
+		data, err := os.ReadFile(outputPath)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(outputPath, data[:len(data)-5], 0o644))
+
+		output, err := runCommandErr(t, "verify-export", outputPath)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "checksum mismatch")
+		assert.Contains(t, output, "❌ "+outputPath+" does not match its checksum")
+	})
+
+	t.Run("missing checksum file", func(t *testing.T) {
+		path := filepath.Join(dir, "plain.csv")
+		runCommand(t, "export", "-o", path)
+		_, err := os.Stat(path + ".sha256")
+		assert.True(t, os.IsNotExist(err), "no sidecar without --checksum")
+
+		_, err = runCommandErr(t, "verify-export", path)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no checksum file")
+	})
+}
+
+func TestExportSplitByStatus(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+	pending1 := insertTestTask(t, "Write tests", "", false)
+	done1 := insertTestTask(t, "Ship release", "v2", true)
+	pending2 := insertTestTask(t, "Update docs", "", false)
+
+	dir := t.TempDir()
+	outputPath := filepath.Join(dir, "report.csv")
+
+	// exportedIDs reads the header and the task ids of a CSV export
+	exportedIDs := func(t *testing.T, path string) ([]string, []string) {
+		file, err := os.Open(path)
+		require.NoError(t, err)
+		defer file.Close()
+		records, err := csv.NewReader(file).ReadAll()
+		require.NoError(t, err)
+		require.NotEmpty(t, records)
+
+		var ids []string
+		for _, record := range records[1:] {
+			ids = append(ids, record[0])
+		}
+		return records[0], ids
+	}
+
+	output := runCommand(t, "export", "-o", outputPath, "--split-by-status", "--sort", "id")
+	donePath := filepath.Join(dir, "report.done.csv")
+	pendingPath := filepath.Join(dir, "report.pending.csv")
+	assert.Contains(t, output, "Tasks exported successfully to "+donePath+" and "+pendingPath)
+
+	header, ids := exportedIDs(t, donePath)
+	assert.Equal(t, []string{"ID", "Title", "Description", "Done", "Created At", "Completed At", "UUID"}, header)
+	assert.Equal(t, []string{fmt.Sprint(done1)}, ids)
+
+	header, ids = exportedIDs(t, pendingPath)
+	assert.Equal(t, []string{"ID", "Title", "Description", "Done", "Created At", "Completed At", "UUID"}, header)
+	assert.Equal(t, []string{fmt.Sprint(pending1), fmt.Sprint(pending2)}, ids)
+
+	_, err := os.Stat(outputPath)
+	assert.True(t, os.IsNotExist(err), "the unsplit file should not be written")
+
+	t.Run("each file gets its own checksum", func(t *testing.T) {
+		runCommand(t, "export", "-o", outputPath, "--split-by-status", "--checksum")
+		assert.FileExists(t, donePath+".sha256")
+		assert.FileExists(t, pendingPath+".sha256")
+	})
+
+	t.Run("files without an extension get the status appended", func(t *testing.T) {
+		base := filepath.Join(dir, "backup")
+		runCommand(t, "export", "-o", base, "--format", "json", "--split-by-status")
+		assert.FileExists(t, base+".done")
+		assert.FileExists(t, base+".pending")
+	})
+}
@@ -0,0 +1,69 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/eduardamirelly/tasker/database"
+	"github.com/eduardamirelly/tasker/dates"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoal(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+	_, err := database.GetDB().Exec(`DELETE FROM app_state`)
+	require.NoError(t, err)
+
+	t.Run("no goal", func(t *testing.T) {
+		assert.Contains(t, runCommand(t, "goal"), "No goal set")
+	})
+
+	now := time.Now()
+	dayStart, _ := dates.DayBounds(now)
+	weekStart, _ := dates.WeekBounds(now, dates.WeekStart())
+
+	created := now.AddDate(0, 0, -30)
+	complete := func(title string, at time.Time) {
+		insertTestTaskWithSpecificTime(t, title, "", true, created, &at)
+	}
+	complete("Done today 1", now)
+	complete("Done today 2", now)
+	complete("Done today 3", now)
+	earlyThisWeek := weekStart.Add(time.Minute)
+	complete("Done early this week", earlyThisWeek)
+	complete("Done last month", now.AddDate(0, 0, -20))
+	insertTestTask(t, "Still pending", "", false)
+
+	today := 3
+	if !earlyThisWeek.Before(dayStart) {
+		// The week started today
+		today++
+	}
+
+	t.Run("set goals", func(t *testing.T) {
+		assert.Contains(t, runCommand(t, "goal", "set", "5", "--per", "day"), "✓ Goal set: 5 task(s) per day")
+		assert.Contains(t, runCommand(t, "goal", "set", "4", "--per", "week"), "✓ Goal set: 4 task(s) per week")
+	})
+
+	t.Run("progress", func(t *testing.T) {
+		output := runCommand(t, "goal")
+		assert.Contains(t, output, fmt.Sprintf("Today: %d/5 tasks (%d%%)\n", today, today*100/5))
+		assert.Contains(t, output, "This week: 4/4 tasks (100%) ✓ goal reached\n")
+	})
+
+	t.Run("remove a goal", func(t *testing.T) {
+		assert.Contains(t, runCommand(t, "goal", "set", "0", "--per", "week"), "✓ Goal per week removed")
+		assert.NotContains(t, runCommand(t, "goal"), "This week")
+	})
+
+	t.Run("invalid goals", func(t *testing.T) {
+		assert.Contains(t, runCommand(t, "goal", "set", "five"), `invalid number of tasks "five"`)
+		assert.Contains(t, runCommand(t, "goal", "set", "3", "--per", "month"), `invalid --per "month"`)
+	})
+}
@@ -0,0 +1,89 @@
+package tests
+
+import (
+	"testing"
+	"text/template"
+
+	"github.com/eduardamirelly/tasker/hooks"
+	"github.com/eduardamirelly/tasker/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingRunner captures the commands it is asked to run instead of executing them
+type recordingRunner struct {
+	calls [][]string
+}
+
+func (r *recordingRunner) run(name string, args ...string) error {
+	r.calls = append(r.calls, append([]string{name}, args...))
+	return nil
+}
+
+func TestHookCommandRun(t *testing.T) {
+	tasks := []models.Task{
+		{ID: 1, Title: "Pay rent"},
+		{ID: 2, Title: "Renew passport"},
+	}
+
+	command, err := hooks.Parse(`notify-send "Overdue task" {{.Title}}`, nil)
+	require.NoError(t, err)
+
+	runner := &recordingRunner{}
+	for _, task := range tasks {
+		require.NoError(t, command.Run(runner.run, task))
+	}
+
+	assert.Equal(t, [][]string{
+		{"notify-send", "Overdue task", "Pay rent"},
+		{"notify-send", "Overdue task", "Renew passport"},
+	}, runner.calls)
+}
+
+func TestHookCommandRendering(t *testing.T) {
+	funcs := template.FuncMap{"shout": func(s string) string { return s + "!" }}
+
+	tests := []struct {
+		name     string
+		line     string
+		task     models.Task
+		expected []string
+	}{
+		{
+			name:     "task values stay a single argument",
+			line:     `echo {{.Title}}`,
+			task:     models.Task{Title: `x"; rm -rf / #`},
+			expected: []string{"echo", `x"; rm -rf / #`},
+		},
+		{
+			name:     "template actions may contain spaces and quotes",
+			line:     `echo {{shout "due"}} {{printf "%d-%s" .ID .Title}}`,
+			task:     models.Task{ID: 4, Title: "Call mom"},
+			expected: []string{"echo", "due!", "4-Call mom"},
+		},
+		{
+			name:     "quotes and escapes",
+			line:     `printf 'a b' "c {{.ID}}" d\ e`,
+			task:     models.Task{ID: 9},
+			expected: []string{"printf", "a b", "c 9", "d e"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			command, err := hooks.Parse(tt.line, funcs)
+			require.NoError(t, err)
+
+			args, err := command.Render(tt.task)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, args)
+		})
+	}
+}
+
+func TestHookCommandParseErrors(t *testing.T) {
+	for _, line := range []string{"", "   ", `echo "unterminated`, "echo {{.Title"} {
+		_, err := hooks.Parse(line, nil)
+		assert.Error(t, err, "line: %q", line)
+	}
+}
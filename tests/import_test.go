@@ -0,0 +1,509 @@
+package tests
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eduardamirelly/tasker/database"
+	"github.com/eduardamirelly/tasker/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeImportFile writes a CSV file with the export header followed by rows
+func writeImportFile(t *testing.T, rows ...string) string {
+	t.Helper()
+
+	content := "ID,Title,Description,Done,Created At,Completed At\n" + strings.Join(rows, "\n") + "\n"
+	path := filepath.Join(t.TempDir(), "tasks.csv")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestImportDryRun(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	setup := func(t *testing.T) (int, string) {
+		clearTestTasks(t)
+		existing := insertTestTask(t, "Existing task", "Keep me", false)
+
+		path := writeImportFile(t,
+			"100,Imported task,From the file,false,2024-05-01 10:00:00,",
+			"101,Completed import,,true,2024-05-01 10:00:00,2024-05-02 11:30:00",
+			fmt.Sprintf("%d,Replacement,Overwritten,true,2024-05-01 10:00:00,2024-05-03 09:00:00", existing),
+			"102,Bad date,,false,yesterday,",
+			"103,Too few columns",
+		)
+		return existing, path
+	}
+
+	for _, strategy := range []string{"skip", "overwrite"} {
+		t.Run("preview matches import with "+strategy, func(t *testing.T) {
+			existing, path := setup(t)
+
+			preview := runCommand(t, "import", path, "--dry-run", "--on-conflict", strategy, "--skip-bad-rows")
+			assert.Contains(t, preview, "Dry run, nothing was imported.")
+			assert.Contains(t, preview, `⚠️  Warning: line 5: invalid created at "yesterday"`)
+			assert.Contains(t, preview, "⚠️  Warning: line 6: expected 6 or 7 columns, got 2")
+
+			// Nothing is written during a dry run
+			assert.Equal(t, 1, getTaskCount(t))
+			assert.Equal(t, "Existing task", getTaskByID(t, existing).Title)
+
+			output := runCommand(t, "import", path, "--on-conflict", strategy, "--skip-bad-rows")
+			assert.Contains(t, output, "✓ Import finished.")
+
+			summary := func(out string) string {
+				return out[strings.Index(out, "added:"):]
+			}
+			assert.Equal(t, summary(preview), summary(output))
+
+			if strategy == "skip" {
+				assert.Contains(t, output, "added: 2, overwritten: 0, skipped: 1, invalid: 2")
+				assert.Equal(t, "Existing task", getTaskByID(t, existing).Title)
+			} else {
+				assert.Contains(t, output, "added: 2, overwritten: 1, skipped: 0, invalid: 2")
+				task := getTaskByID(t, existing)
+				assert.Equal(t, "Replacement", task.Title)
+				assert.True(t, task.Done)
+			}
+
+			assert.Equal(t, 3, getTaskCount(t))
+			imported := getTaskByID(t, 101)
+			require.NotNil(t, imported)
+			assert.Equal(t, "Completed import", imported.Title)
+			assert.True(t, imported.Done)
+
+			var completedAt string
+			err := database.GetDB().QueryRow(`SELECT datetime(completed_at) FROM tasks WHERE id = 101`).Scan(&completedAt)
+			require.NoError(t, err)
+			assert.Equal(t, "2024-05-02 11:30:00", completedAt)
+		})
+	}
+
+	t.Run("duplicate ids in the file", func(t *testing.T) {
+		clearTestTasks(t)
+		path := writeImportFile(t,
+			"200,First copy,,false,2024-05-01 10:00:00,",
+			"200,Second copy,,false,2024-05-01 10:00:00,",
+		)
+
+		output := runCommand(t, "import", path, "--dry-run")
+		assert.Contains(t, output, "added: 1, overwritten: 0, skipped: 1, invalid: 0")
+	})
+
+	t.Run("invalid strategy", func(t *testing.T) {
+		_, path := setup(t)
+		output := runCommand(t, "import", path, "--on-conflict", "merge")
+		assert.Contains(t, output, "invalid --on-conflict")
+		assert.Equal(t, 1, getTaskCount(t))
+	})
+}
+
+func TestImportSkipBadRows(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	path := writeImportFile(t,
+		"300,Good one,,false,2024-05-01 10:00:00,",
+		"301,Bad boolean,,maybe,2024-05-01 10:00:00,",
+		"302,Good two,,true,2024-05-01 10:00:00,2024-05-02 10:00:00",
+		"303,Bad date,,false,2024-05-01 10:00:00,last week",
+		"304,Wrong column count,,false",
+		"305,Good three,Described,false,2024-05-01 10:00:00,",
+	)
+
+	t.Run("bad rows fail the import by default", func(t *testing.T) {
+		clearTestTasks(t)
+		output := runCommand(t, "import", path)
+
+		assert.Contains(t, output, `⚠️  Warning: line 3: invalid done value "maybe"`)
+		assert.Contains(t, output, "Error importing tasks: 3 malformed row(s), nothing was imported")
+		assert.Equal(t, 0, getTaskCount(t))
+	})
+
+	t.Run("good rows are imported with --skip-bad-rows", func(t *testing.T) {
+		clearTestTasks(t)
+		output := runCommand(t, "import", path, "--skip-bad-rows")
+
+		assert.Contains(t, output, `⚠️  Warning: line 3: invalid done value "maybe"`)
+		assert.Contains(t, output, `⚠️  Warning: line 5: invalid completed at "last week"`)
+		assert.Contains(t, output, "⚠️  Warning: line 6: expected 6 or 7 columns, got 4")
+		assert.Contains(t, output, "✓ Import finished. added: 3, overwritten: 0, skipped: 0, invalid: 3 (lines 3, 5, 6)\n")
+
+		assert.Equal(t, 3, getTaskCount(t))
+		for _, id := range []int{300, 302, 305} {
+			assert.NotNil(t, getTaskByID(t, id), "task %d", id)
+		}
+		for _, id := range []int{301, 303, 304} {
+			assert.Nil(t, getTaskByID(t, id), "task %d", id)
+		}
+	})
+
+	t.Run("dry run warns the import would fail", func(t *testing.T) {
+		clearTestTasks(t)
+		output := runCommand(t, "import", path, "--dry-run")
+		assert.Contains(t, output, "Warning: the import would fail on the malformed rows unless --skip-bad-rows is given")
+	})
+}
+
+func TestImportPreciseRoundTrip(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+	base := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	created := map[int]time.Time{}
+	for i, offset := range []time.Duration{300, 100, 250} {
+		createdAt := base.Add(offset * time.Millisecond)
+		completedAt := createdAt.Add(1500 * time.Microsecond)
+		id := insertTestTaskWithSpecificTime(t, fmt.Sprintf("Same second %d", i+1), "", true, createdAt, &completedAt)
+		created[id] = createdAt
+	}
+
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first.csv")
+	runCommand(t, "export", "--precise", "-o", first)
+
+	clearTestTasks(t)
+	output := runCommand(t, "import", first)
+	assert.Contains(t, output, "added: 3")
+
+	second := filepath.Join(dir, "second.csv")
+	runCommand(t, "export", "--precise", "-o", second)
+
+	before, err := os.ReadFile(first)
+	require.NoError(t, err)
+	after, err := os.ReadFile(second)
+	require.NoError(t, err)
+	assert.Equal(t, string(before), string(after), "rows and timestamps should survive the round trip")
+
+	for id, createdAt := range created {
+		var stored time.Time
+		require.NoError(t, database.GetDB().QueryRow(`SELECT created_at FROM tasks WHERE id = ?`, id).Scan(&stored))
+		assert.True(t, createdAt.Equal(stored), "task %d: expected %v, got %v", id, createdAt, stored)
+	}
+}
+
+func TestImportSkipHeader(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	t.Run("exported file round-trips through import", func(t *testing.T) {
+		clearTestTasks(t)
+		createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+		completedAt := time.Date(2024, 5, 2, 11, 30, 0, 0, time.UTC)
+		pending := insertTestTaskWithSpecificTime(t, "Write docs", "With, commas", false, createdAt, nil)
+		done := insertTestTaskWithSpecificTime(t, "Ship release", "", true, createdAt, &completedAt)
+
+		path := filepath.Join(t.TempDir(), "tasks.csv")
+		runCommand(t, "export", "-o", path)
+
+		clearTestTasks(t)
+		output := runCommand(t, "import", path)
+		assert.Contains(t, output, "✓ Import finished. added: 2, overwritten: 0, skipped: 0, invalid: 0")
+
+		task := getTaskByID(t, pending)
+		assert.Equal(t, "Write docs", task.Title)
+		assert.Equal(t, "With, commas", task.Description)
+		assert.False(t, task.Done)
+
+		var stored sql.NullTime
+		require.NoError(t, database.GetDB().QueryRow(`SELECT completed_at FROM tasks WHERE id = ?`, done).Scan(&stored))
+		require.True(t, stored.Valid)
+		assert.True(t, completedAt.Equal(stored.Time), "completed at %v", stored.Time)
+	})
+
+	t.Run("spreadsheet header in another case is skipped", func(t *testing.T) {
+		clearTestTasks(t)
+		path := filepath.Join(t.TempDir(), "sheet.csv")
+		content := "id, title, description, done, created at, completed at\n300,From a sheet,,false,2024-05-01 10:00:00,\n"
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+		output := runCommand(t, "import", path)
+		assert.Contains(t, output, "added: 1, overwritten: 0, skipped: 0, invalid: 0")
+		assert.Equal(t, "From a sheet", getTaskByID(t, 300).Title)
+	})
+
+	t.Run("header is read as data when not skipped", func(t *testing.T) {
+		clearTestTasks(t)
+		path := writeImportFile(t, "301,Real task,,false,2024-05-01 10:00:00,")
+
+		output := runCommand(t, "import", path, "--skip-header=false", "--dry-run")
+		assert.Contains(t, output, "⚠️  Warning: line 1: invalid id")
+		assert.Contains(t, output, "added: 1, overwritten: 0, skipped: 0, invalid: 1")
+		assert.Equal(t, 0, getTaskCount(t))
+	})
+
+	t.Run("file without a header keeps its first row", func(t *testing.T) {
+		clearTestTasks(t)
+		path := filepath.Join(t.TempDir(), "rows.csv")
+		require.NoError(t, os.WriteFile(path, []byte("302,First row,,false,2024-05-01 10:00:00,\n"), 0644))
+
+		output := runCommand(t, "import", path)
+		assert.Contains(t, output, "added: 1")
+		assert.Equal(t, "First row", getTaskByID(t, 302).Title)
+	})
+}
+
+func TestImportJSON(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	writeJSON := func(t *testing.T, content string) string {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), "tasks.json")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+
+	t.Run("pending and completed tasks", func(t *testing.T) {
+		clearTestTasks(t)
+		path := writeJSON(t, `[
+  {
+    "id": 400,
+    "title": "Write report",
+    "description": "Quarterly numbers",
+    "done": false,
+    "created_at": "2024-05-01T10:00:00Z",
+    "priority": "High",
+    "due_date": "2024-05-10T17:00:00Z",
+    "tags": ["work", "Writing"]
+  },
+  {
+    "id": 401,
+    "title": "Buy milk",
+    "created_at": "2024-05-02T09:00:00Z",
+    "completed_at": "2024-05-02T18:30:00Z",
+    "completion_note": "Oat milk"
+  }
+]`)
+		output := runCommand(t, "import", path)
+		assert.Contains(t, output, "✓ Import finished. added: 2, overwritten: 0, skipped: 0, invalid: 0\n")
+
+		pending := getTaskByID(t, 400)
+		require.NotNil(t, pending)
+		assert.Equal(t, "Write report", pending.Title)
+		assert.Equal(t, "Quarterly numbers", pending.Description)
+		assert.False(t, pending.Done)
+
+		var (
+			priority    string
+			dueDate     time.Time
+			completedAt sql.NullTime
+		)
+		require.NoError(t, database.GetDB().QueryRow(`SELECT priority, due_date, completed_at FROM tasks WHERE id = 400`).
+			Scan(&priority, &dueDate, &completedAt))
+		assert.Equal(t, "high", priority)
+		assert.True(t, dueDate.Equal(time.Date(2024, 5, 10, 17, 0, 0, 0, time.UTC)))
+		assert.False(t, completedAt.Valid)
+		assert.Contains(t, runCommand(t, "show", "400"), "Tags: work, writing")
+
+		completed := getTaskByID(t, 401)
+		require.NotNil(t, completed)
+		assert.True(t, completed.Done, "a completion time marks the task done")
+		var note string
+		require.NoError(t, database.GetDB().QueryRow(`SELECT priority, completed_at, completion_note FROM tasks WHERE id = 401`).
+			Scan(&priority, &completedAt, &note))
+		assert.Equal(t, "medium", priority)
+		require.True(t, completedAt.Valid)
+		assert.True(t, completedAt.Time.Equal(time.Date(2024, 5, 2, 18, 30, 0, 0, time.UTC)))
+		assert.Equal(t, "Oat milk", note)
+	})
+
+	t.Run("done without a completion time is completed now", func(t *testing.T) {
+		clearTestTasks(t)
+		before := time.Now().Add(-time.Second)
+		path := writeJSON(t, `[{"id": 410, "title": "Old chore", "done": true, "created_at": "2024-05-01T10:00:00Z"}]`)
+		runCommand(t, "import", path)
+
+		var completedAt sql.NullTime
+		require.NoError(t, database.GetDB().QueryRow(`SELECT completed_at FROM tasks WHERE id = 410`).Scan(&completedAt))
+		require.True(t, completedAt.Valid)
+		assert.True(t, completedAt.Time.After(before))
+	})
+
+	t.Run("invalid tasks are reported with their line", func(t *testing.T) {
+		clearTestTasks(t)
+		path := writeJSON(t, `[
+  {"id": 420, "title": "Fine", "created_at": "2024-05-01T10:00:00Z"},
+  {"id": 421, "title": "  ", "created_at": "2024-05-01T10:00:00Z"},
+  {"id": 422, "title": "Bad priority", "priority": "urgent"},
+  {"id": 423, "title": "Bad date", "created_at": "yesterday"},
+  {"id": 424, "title": "Backwards", "created_at": "2024-05-02T10:00:00Z", "completed_at": "2024-05-01T10:00:00Z"}
+]`)
+		output := runCommand(t, "import", path)
+		assert.Contains(t, output, "⚠️  Warning: line 3: title cannot be empty")
+		assert.Contains(t, output, `⚠️  Warning: line 4: invalid priority "urgent"`)
+		assert.Contains(t, output, "⚠️  Warning: line 5: ")
+		assert.Contains(t, output, "⚠️  Warning: line 6: completed at 2024-05-01 10:00:00, before it was created at 2024-05-02 10:00:00")
+		assert.Contains(t, output, "Error importing tasks: 4 malformed row(s), nothing was imported")
+		assert.Equal(t, 0, getTaskCount(t))
+
+		output = runCommand(t, "import", path, "--skip-bad-rows")
+		assert.Contains(t, output, "added: 1, overwritten: 0, skipped: 0, invalid: 4 (lines 3, 4, 5, 6)")
+		assert.NotNil(t, getTaskByID(t, 420))
+	})
+
+	t.Run("uuid is kept and found by show", func(t *testing.T) {
+		clearTestTasks(t)
+		path := writeJSON(t, `[{"id": 440, "title": "Stable task", "uuid": "6F1C2B3A-4D5E-4F60-8A7B-9C0D1E2F3A4B"}]`)
+		output := runCommand(t, "import", path)
+		assert.Contains(t, output, "added: 1")
+
+		output = runCommand(t, "show", "6f1c2b3a-4d5e-4f60-8a7b-9c0d1e2f3a4b")
+		assert.Contains(t, output, "Stable task")
+		assert.Contains(t, output, "6f1c2b3a-4d5e-4f60-8a7b-9c0d1e2f3a4b")
+	})
+
+	t.Run("invalid or duplicate uuids are rejected", func(t *testing.T) {
+		clearTestTasks(t)
+		path := writeJSON(t, `[{"id": 441, "title": "Bad uuid", "uuid": "not-a-uuid"}]`)
+		output := runCommand(t, "import", path)
+		assert.Contains(t, output, `⚠️  Warning: line 1: invalid uuid "not-a-uuid"`)
+		assert.Equal(t, 0, getTaskCount(t))
+
+		path = writeJSON(t, `[
+  {"id": 442, "title": "First", "uuid": "6f1c2b3a-4d5e-4f60-8a7b-9c0d1e2f3a4b"},
+  {"id": 443, "title": "Second", "uuid": "6f1c2b3a-4d5e-4f60-8a7b-9c0d1e2f3a4b"}
+]`)
+		output = runCommand(t, "import", path)
+		assert.Contains(t, output, "Error importing tasks: uuid 6f1c2b3a-4d5e-4f60-8a7b-9c0d1e2f3a4b is already used by task 442")
+		assert.Equal(t, 0, getTaskCount(t), "the transaction is rolled back")
+	})
+
+	t.Run("overwrite replaces the tags", func(t *testing.T) {
+		clearTestTasks(t)
+		_, err := database.GetDB().Exec(`DELETE FROM task_tags`)
+		require.NoError(t, err)
+		path := writeJSON(t, `[
+  {"id": 450, "title": "Tagged", "tags": ["work", "urgent"]},
+  {"id": 451, "title": "Kept tags", "tags": ["home"]},
+  {"id": 452, "title": "Cleared tags", "tags": ["work"]}
+]`)
+		runCommand(t, "import", path)
+
+		path = writeJSON(t, `[
+  {"id": 450, "title": "Retagged", "tags": ["home"]},
+  {"id": 451, "title": "No tags given"},
+  {"id": 452, "title": "Empty tags", "tags": []}
+]`)
+		output := runCommand(t, "import", path, "--on-conflict", "overwrite")
+		assert.Contains(t, output, "overwritten: 3")
+
+		assert.Contains(t, runCommand(t, "show", "450"), "Tags: home\n")
+		assert.Contains(t, runCommand(t, "show", "451"), "Tags: home\n", "tags the file leaves out are kept")
+		assert.NotContains(t, runCommand(t, "show", "452"), "Tags:")
+	})
+
+	t.Run("malformed JSON fails the whole file", func(t *testing.T) {
+		clearTestTasks(t)
+		path := writeJSON(t, "[\n  {\"id\": 430, \"title\": \"Fine\"},\n  {\"id\": 431, \"title\": \n]")
+		output := runCommand(t, "import", path)
+		assert.Contains(t, output, "Error importing tasks: invalid JSON on line 3")
+		assert.Equal(t, 0, getTaskCount(t))
+	})
+
+	t.Run("round trip through a manifest export", func(t *testing.T) {
+		clearTestTasks(t)
+		runCommand(t, "add", "Exported task", "--priority", "low", "--tag", "home")
+		exportPath := filepath.Join(t.TempDir(), "backup.json")
+		runCommand(t, "export", "--format", "json", "--manifest", "-o", exportPath)
+
+		clearTestTasks(t)
+		output := runCommand(t, "import", exportPath)
+		assert.Contains(t, output, "added: 1")
+		output = runCommand(t, "list", "--priority", "low", "--tag", "home")
+		assert.Contains(t, output, "Exported task")
+	})
+}
+
+func TestImportCSVUUID(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	uuidOf := func(t *testing.T, id int) string {
+		t.Helper()
+		var uuid string
+		require.NoError(t, database.GetDB().QueryRow(`SELECT uuid FROM tasks WHERE id = ?`, id).Scan(&uuid))
+		return uuid
+	}
+
+	t.Run("exported uuids survive the round trip", func(t *testing.T) {
+		clearTestTasks(t)
+		createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+		first := insertTestTaskWithSpecificTime(t, "Write docs", "", false, createdAt, nil)
+		second := insertTestTaskWithSpecificTime(t, "Ship release", "", false, createdAt, nil)
+		want := map[int]string{first: uuidOf(t, first), second: uuidOf(t, second)}
+
+		path := filepath.Join(t.TempDir(), "tasks.csv")
+		runCommand(t, "export", "-o", path)
+		records := readCSVFile(t, path)
+		assert.Equal(t, "UUID", records[0][6])
+		assert.Equal(t, want[first], records[1][6])
+
+		clearTestTasks(t)
+		output := runCommand(t, "import", path)
+		assert.Contains(t, output, "added: 2, overwritten: 0, skipped: 0, invalid: 0")
+		for id, uuid := range want {
+			assert.Equal(t, uuid, uuidOf(t, id))
+		}
+		assert.Contains(t, runCommand(t, "show", want[second]), "Ship release")
+	})
+
+	t.Run("file without a uuid column gets new uuids", func(t *testing.T) {
+		clearTestTasks(t)
+		path := writeImportFile(t, "500,Legacy row,,false,2024-05-01 10:00:00,")
+
+		output := runCommand(t, "import", path)
+		assert.Contains(t, output, "added: 1")
+		assert.True(t, models.ValidUUID(uuidOf(t, 500)))
+	})
+
+	t.Run("empty uuid gets a new one", func(t *testing.T) {
+		clearTestTasks(t)
+		path := filepath.Join(t.TempDir(), "tasks.csv")
+		content := strings.Join(models.CSVHeader, ",") + "\n501,No uuid,,false,2024-05-01 10:00:00,,\n"
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+		runCommand(t, "import", path)
+		assert.True(t, models.ValidUUID(uuidOf(t, 501)))
+	})
+
+	t.Run("invalid uuid is a bad row", func(t *testing.T) {
+		clearTestTasks(t)
+		path := filepath.Join(t.TempDir(), "tasks.csv")
+		content := strings.Join(models.CSVHeader, ",") + "\n502,Bad uuid,,false,2024-05-01 10:00:00,,not-a-uuid\n"
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+		output := runCommand(t, "import", path, "--dry-run")
+		assert.Contains(t, output, `Warning: line 2: invalid uuid "not-a-uuid"`)
+	})
+
+	t.Run("uuid of another task fails the import", func(t *testing.T) {
+		clearTestTasks(t)
+		existing := insertTestTaskWithSpecificTime(t, "Existing", "", false, time.Now(), nil)
+		taken := uuidOf(t, existing)
+
+		path := filepath.Join(t.TempDir(), "tasks.csv")
+		content := strings.Join(models.CSVHeader, ",") + "\n" +
+			"503,First copy,,false,2024-05-01 10:00:00,," + taken + "\n"
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+		output := runCommand(t, "import", path)
+		assert.Contains(t, output, fmt.Sprintf("Error importing tasks: uuid %s is already used by task %d", taken, existing))
+		assert.Equal(t, 1, getTaskCount(t), "nothing is imported")
+	})
+}
@@ -58,7 +58,7 @@ func TestCompleteTaskWorkflow(t *testing.T) {
 	// Step 6: Complete the first task (Buy groceries)
 	completedTime := time.Now()
 	updateQuery := `UPDATE tasks SET done = TRUE, completed_at = ? WHERE id = ?`
-	_, err := database.DB.Exec(updateQuery, completedTime, taskID1)
+	_, err := database.GetDB().Exec(updateQuery, completedTime, taskID1)
 	require.NoError(t, err)
 
 	// Step 7: Verify task is marked as completed
@@ -83,7 +83,7 @@ func TestCompleteTaskWorkflow(t *testing.T) {
 	taskIDs := []int{taskID2, taskID3}
 	for _, id := range taskIDs {
 		completedTime := time.Now()
-		_, err := database.DB.Exec(updateQuery, completedTime, id)
+		_, err := database.GetDB().Exec(updateQuery, completedTime, id)
 		require.NoError(t, err)
 	}
 
@@ -126,7 +126,7 @@ func TestTaskLifecycle(t *testing.T) {
 	var title, description string
 	var done bool
 
-	err := database.DB.QueryRow(query, taskID).Scan(&id, &title, &description, &done)
+	err := database.GetDB().QueryRow(query, taskID).Scan(&id, &title, &description, &done)
 	require.NoError(t, err)
 
 	assert.Equal(t, taskID, id)
@@ -137,11 +137,11 @@ func TestTaskLifecycle(t *testing.T) {
 	// Phase 3: Completion
 	completedTime := time.Now()
 	updateQuery := `UPDATE tasks SET done = TRUE, completed_at = ? WHERE id = ?`
-	_, err = database.DB.Exec(updateQuery, completedTime, taskID)
+	_, err = database.GetDB().Exec(updateQuery, completedTime, taskID)
 	require.NoError(t, err)
 
 	// Verify completion
-	err = database.DB.QueryRow(query, taskID).Scan(&id, &title, &description, &done)
+	err = database.GetDB().QueryRow(query, taskID).Scan(&id, &title, &description, &done)
 	require.NoError(t, err)
 	assert.True(t, done)
 
@@ -198,7 +198,7 @@ func TestMixedTaskStates(t *testing.T) {
 	updateQuery := `UPDATE tasks SET done = TRUE, completed_at = ? WHERE id = ?`
 	for i, id := range taskIDs {
 		if testCases[i].shouldComplete {
-			_, err := database.DB.Exec(updateQuery, completedTime, id)
+			_, err := database.GetDB().Exec(updateQuery, completedTime, id)
 			require.NoError(t, err)
 		}
 	}
@@ -235,7 +235,7 @@ func TestErrorScenarios(t *testing.T) {
 	// Scenario 1: Try to complete non-existent task
 	query := `SELECT id FROM tasks WHERE id = ?`
 	var id int
-	err := database.DB.QueryRow(query, 999).Scan(&id)
+	err := database.GetDB().QueryRow(query, 999).Scan(&id)
 	assert.Error(t, err, "Non-existent task should return error")
 
 	// Scenario 2: Add task and then try various operations
@@ -247,7 +247,7 @@ func TestErrorScenarios(t *testing.T) {
 	// Complete the task
 	completedTime := time.Now()
 	updateQuery := `UPDATE tasks SET done = TRUE, completed_at = ? WHERE id = ?`
-	_, err = database.DB.Exec(updateQuery, completedTime, taskID)
+	_, err = database.GetDB().Exec(updateQuery, completedTime, taskID)
 	require.NoError(t, err)
 
 	// Try to complete it again (should work without error)
@@ -257,7 +257,7 @@ func TestErrorScenarios(t *testing.T) {
 
 	// Update again (should not cause error)
 	newCompletedTime := time.Now()
-	_, err = database.DB.Exec(updateQuery, newCompletedTime, taskID)
+	_, err = database.GetDB().Exec(updateQuery, newCompletedTime, taskID)
 	require.NoError(t, err)
 
 	// Verify task is still completed
@@ -285,7 +285,7 @@ func TestConcurrentOperations(t *testing.T) {
 			description := fmt.Sprintf("Description %d", index)
 
 			query := `INSERT INTO tasks (title, description, done) VALUES (?, ?, ?)`
-			result, err := database.DB.Exec(query, title, description, false)
+			result, err := database.GetDB().Exec(query, title, description, false)
 			if err != nil {
 				errChan <- err
 				taskIDChan <- 0
@@ -320,7 +320,7 @@ func TestConcurrentOperations(t *testing.T) {
 
 	for _, taskID := range taskIDs {
 		go func(id int) {
-			_, err := database.DB.Exec(updateQuery, completedTime, id)
+			_, err := database.GetDB().Exec(updateQuery, completedTime, id)
 			completionErrChan <- err
 		}(taskID)
 	}
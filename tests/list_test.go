@@ -1,11 +1,17 @@
 package tests
 
 import (
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/eduardamirelly/tasker/cmd"
 	"github.com/eduardamirelly/tasker/database"
+	"github.com/eduardamirelly/tasker/dates"
+	"github.com/eduardamirelly/tasker/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -21,7 +27,7 @@ func TestListTasks(t *testing.T) {
 		// Use reflection or create a wrapper to access the private listTasks function
 		// For now, we'll test the database query directly
 		query := `SELECT id, title, description, done, created_at, completed_at FROM tasks`
-		rows, err := database.DB.Query(query)
+		rows, err := database.GetDB().Query(query)
 		require.NoError(t, err)
 		defer rows.Close()
 
@@ -40,7 +46,7 @@ func TestListTasks(t *testing.T) {
 
 		// Query tasks
 		query := `SELECT id, title, description, done, created_at, completed_at FROM tasks`
-		rows, err := database.DB.Query(query)
+		rows, err := database.GetDB().Query(query)
 		require.NoError(t, err)
 		defer rows.Close()
 
@@ -84,7 +90,7 @@ func TestListTasks(t *testing.T) {
 
 		// Query and verify tasks
 		query := `SELECT id, title, description, done FROM tasks ORDER BY id`
-		rows, err := database.DB.Query(query)
+		rows, err := database.GetDB().Query(query)
 		require.NoError(t, err)
 		defer rows.Close()
 
@@ -110,13 +116,13 @@ func TestListTasks(t *testing.T) {
 		// Insert a completed task with completed_at timestamp
 		completedTime := time.Now()
 		query := `INSERT INTO tasks (title, description, done, completed_at) VALUES (?, ?, ?, ?)`
-		_, err := database.DB.Exec(query, "Completed Task", "This task is done", true, completedTime)
+		_, err := database.GetDB().Exec(query, "Completed Task", "This task is done", true, completedTime)
 		require.NoError(t, err)
 
 		// Query and verify
 		queryCheck := `SELECT completed_at FROM tasks WHERE title = ?`
 		var retrievedCompletedAt time.Time
-		err = database.DB.QueryRow(queryCheck, "Completed Task").Scan(&retrievedCompletedAt)
+		err = database.GetDB().QueryRow(queryCheck, "Completed Task").Scan(&retrievedCompletedAt)
 		require.NoError(t, err)
 
 		// Check if completed time is approximately correct (within 1 second)
@@ -134,10 +140,10 @@ func TestListTasks(t *testing.T) {
 		// Count tasks by status
 		var completedCount, incompleteCount int
 
-		err := database.DB.QueryRow("SELECT COUNT(*) FROM tasks WHERE done = true").Scan(&completedCount)
+		err := database.GetDB().QueryRow("SELECT COUNT(*) FROM tasks WHERE done = true").Scan(&completedCount)
 		require.NoError(t, err)
 
-		err = database.DB.QueryRow("SELECT COUNT(*) FROM tasks WHERE done = false").Scan(&incompleteCount)
+		err = database.GetDB().QueryRow("SELECT COUNT(*) FROM tasks WHERE done = false").Scan(&incompleteCount)
 		require.NoError(t, err)
 
 		assert.Equal(t, 1, completedCount)
@@ -167,7 +173,7 @@ func TestListTasksWithLargeDataset(t *testing.T) {
 
 	// Verify tasks have proper IDs (should be sequential)
 	query := `SELECT id, title FROM tasks ORDER BY id`
-	rows, err := database.DB.Query(query)
+	rows, err := database.GetDB().Query(query)
 	require.NoError(t, err)
 	defer rows.Close()
 
@@ -190,11 +196,1026 @@ func TestListTasksErrorHandling(t *testing.T) {
 	defer cleanup()
 
 	// Close the database to simulate an error
-	database.DB.Close()
+	database.GetDB().Close()
 
 	// Try to query tasks - should get an error
 	query := `SELECT id, title, description, done, created_at, completed_at FROM tasks`
-	rows, err := database.DB.Query(query)
+	rows, err := database.GetDB().Query(query)
 	assert.Error(t, err)
 	assert.Nil(t, rows)
 }
+
+func TestListCompletedAtFormatting(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	completedTime := time.Date(2024, 3, 15, 14, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		completedAt interface{}
+		expected    string
+	}{
+		{
+			name:        "nil completion time",
+			completedAt: nil,
+			expected:    "Completed At: N/A",
+		},
+		{
+			name:        "zero completion time",
+			completedAt: time.Time{},
+			expected:    "Completed At: N/A",
+		},
+		{
+			name:        "real completion time",
+			completedAt: completedTime,
+			expected:    "Completed At: 2024-03-15 14:30:00",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearTestTasks(t)
+
+			taskID := insertTestTask(t, "Formatted Task", "Description", true)
+			_, err := database.GetDB().Exec(`UPDATE tasks SET completed_at = ? WHERE id = ?`, tt.completedAt, taskID)
+			require.NoError(t, err)
+
+			output := runCommand(t, "list")
+
+			assert.Contains(t, output, tt.expected)
+			assert.NotContains(t, output, "0001-01-01")
+		})
+	}
+}
+
+func TestListTitleFilters(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+	insertTestTask(t, "Write report", "", false)
+	insertTestTask(t, "Review REPORT draft", "", true)
+	insertTestTask(t, "Buy groceries", "", false)
+	insertTestTask(t, "100% done_task", "", false)
+
+	tests := []struct {
+		name     string
+		args     []string
+		included []string
+		excluded []string
+	}{
+		{
+			name:     "title contains is case-insensitive",
+			args:     []string{"--title-contains", "report"},
+			included: []string{"Write report", "Review REPORT draft"},
+			excluded: []string{"Buy groceries", "100% done_task"},
+		},
+		{
+			name:     "title prefix",
+			args:     []string{"--title-prefix", "re"},
+			included: []string{"Review REPORT draft"},
+			excluded: []string{"Write report", "Buy groceries", "100% done_task"},
+		},
+		{
+			name:     "filters compose",
+			args:     []string{"--title-prefix", "write", "--title-contains", "port"},
+			included: []string{"Write report"},
+			excluded: []string{"Review REPORT draft", "Buy groceries", "100% done_task"},
+		},
+		{
+			name:     "wildcards are matched literally",
+			args:     []string{"--title-contains", "%"},
+			included: []string{"100% done_task"},
+			excluded: []string{"Write report", "Review REPORT draft", "Buy groceries"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := runCommand(t, append([]string{"list"}, tt.args...)...)
+
+			for _, title := range tt.included {
+				assert.Contains(t, output, title)
+			}
+			for _, title := range tt.excluded {
+				assert.NotContains(t, output, title)
+			}
+		})
+	}
+
+	t.Run("no matches", func(t *testing.T) {
+		output := runCommand(t, "list", "--title-contains", "nothing like this")
+		assert.Contains(t, output, "No tasks found")
+	})
+}
+
+func TestListStableOrdering(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+
+	// Several tasks share a created_at, as batch inserts commonly do
+	query := `INSERT INTO tasks (title, description, created_at) VALUES (?, ?, ?)`
+	var ids []int
+	for i, createdAt := range []string{"2023-01-02", "2023-01-01", "2023-01-01", "2023-01-01"} {
+		result, err := database.GetDB().Exec(query, fmt.Sprintf("Batch task %d", i+1), "", createdAt)
+		require.NoError(t, err)
+		id, err := result.LastInsertId()
+		require.NoError(t, err)
+		ids = append(ids, int(id))
+	}
+
+	// Oldest first, with same-timestamp tasks ordered by id
+	expected := []int{ids[1], ids[2], ids[3], ids[0]}
+	for i := 0; i < 5; i++ {
+		output := runCommand(t, "list")
+		assert.Equal(t, expected, listedTaskIDs(t, output))
+	}
+}
+
+func TestListJSONLines(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	t.Run("one JSON object per line", func(t *testing.T) {
+		clearTestTasks(t)
+		createdAt := time.Date(2024, 2, 1, 10, 0, 0, 0, time.UTC)
+		completedAt := time.Date(2024, 2, 2, 11, 0, 0, 0, time.UTC)
+		firstID := insertTestTaskWithSpecificTime(t, "Write report", "Quarterly", true, createdAt, &completedAt)
+		secondID := insertTestTaskWithSpecificTime(t, "Review report", "", false, createdAt, nil)
+		insertTestTaskWithSpecificTime(t, "Buy groceries", "", false, createdAt, nil)
+
+		output := runCommand(t, "list", "--title-contains", "report", "--jsonl")
+
+		lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+		require.Len(t, lines, 2)
+
+		var first, second models.Task
+		require.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+		require.NoError(t, json.Unmarshal([]byte(lines[1]), &second))
+
+		assert.Equal(t, firstID, first.ID)
+		assert.Equal(t, "Write report", first.Title)
+		assert.Equal(t, "Quarterly", first.Description)
+		assert.True(t, first.Done)
+		assert.True(t, createdAt.Equal(first.CreatedAt))
+		require.NotNil(t, first.CompletedAt)
+		assert.True(t, completedAt.Equal(*first.CompletedAt))
+
+		assert.Equal(t, secondID, second.ID)
+		assert.False(t, second.Done)
+		assert.Nil(t, second.CompletedAt)
+	})
+
+	t.Run("no matches prints nothing", func(t *testing.T) {
+		clearTestTasks(t)
+
+		output := runCommand(t, "list", "--jsonl")
+		assert.Empty(t, output)
+	})
+}
+
+func TestListCount(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	t.Run("empty database", func(t *testing.T) {
+		clearTestTasks(t)
+		assert.Equal(t, "0\n", runCommand(t, "list", "--count"))
+	})
+
+	clearTestTasks(t)
+	insertTestTask(t, "Fix login bug", "", false)
+	insertTestTask(t, "Fix export bug", "", true)
+	insertTestTask(t, "Write docs", "", false)
+
+	t.Run("unfiltered", func(t *testing.T) {
+		assert.Equal(t, "3\n", runCommand(t, "list", "--count"))
+	})
+
+	t.Run("filtered", func(t *testing.T) {
+		assert.Equal(t, "2\n", runCommand(t, "list", "--count", "--title-contains", "bug"))
+		assert.Equal(t, "1\n", runCommand(t, "list", "--count", "--title-prefix", "write"))
+		assert.Equal(t, "0\n", runCommand(t, "list", "--count", "--title-prefix", "nothing"))
+	})
+}
+
+func TestListStatusCounts(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+	insertTestTask(t, "Fix login bug", "", false)
+	insertTestTask(t, "Fix export bug", "", true)
+	insertTestTask(t, "Fix import bug", "", true)
+	insertTestTask(t, "Write docs", "", false)
+	insertTestTask(t, "Write changelog", "", false)
+
+	t.Run("unfiltered", func(t *testing.T) {
+		assert.Equal(t, "2\n", runCommand(t, "list", "--done-count"))
+		assert.Equal(t, "3\n", runCommand(t, "list", "--pending-count"))
+	})
+
+	t.Run("filtered", func(t *testing.T) {
+		assert.Equal(t, "2\n", runCommand(t, "list", "--done-count", "--title-contains", "bug"))
+		assert.Equal(t, "1\n", runCommand(t, "list", "--pending-count", "--title-contains", "bug"))
+		assert.Equal(t, "0\n", runCommand(t, "list", "--done-count", "--title-prefix", "write"))
+	})
+
+	t.Run("only one count at a time", func(t *testing.T) {
+		_, err := runCommandErr(t, "list", "--done-count", "--pending-count")
+		assert.Error(t, err)
+	})
+}
+
+func TestListDueHorizonFilters(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	now := time.Now()
+	_, endOfToday := dates.DayBounds(now)
+	_, endOfWeek := dates.WeekBounds(now, dates.WeekStart())
+
+	clearTestTasks(t)
+	dueToday := insertTestTaskWithDueDate(t, "Due today", false, &now)
+	laterThisWeek := endOfWeek.Add(-time.Minute)
+	dueLaterThisWeek := insertTestTaskWithDueDate(t, "Due later this week", false, &laterThisWeek)
+	nextWeek := endOfWeek.Add(2 * time.Hour)
+	dueNextWeek := insertTestTaskWithDueDate(t, "Due next week", false, &nextWeek)
+	insertTestTaskWithDueDate(t, "No due date", false, nil)
+
+	// On the last day of the week "later this week" is also today
+	laterIsToday := laterThisWeek.Before(endOfToday)
+
+	t.Run("due today", func(t *testing.T) {
+		expected := []int{dueToday}
+		if laterIsToday {
+			expected = append(expected, dueLaterThisWeek)
+		}
+		assert.ElementsMatch(t, expected, listedTaskIDs(t, runCommand(t, "list", "--due-today")))
+	})
+
+	t.Run("due this week", func(t *testing.T) {
+		ids := listedTaskIDs(t, runCommand(t, "list", "--due-this-week"))
+		assert.ElementsMatch(t, []int{dueToday, dueLaterThisWeek}, ids)
+		assert.NotContains(t, ids, dueNextWeek)
+	})
+
+	t.Run("combines with other filters", func(t *testing.T) {
+		ids := listedTaskIDs(t, runCommand(t, "list", "--due-this-week", "--title-contains", "later"))
+		assert.Equal(t, []int{dueLaterThisWeek}, ids)
+	})
+}
+
+func TestListSortWithConfigDefaults(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+	charlie := insertTestTask(t, "charlie", "", false)
+	alpha := insertTestTask(t, "Alpha", "", true)
+	bravo := insertTestTask(t, "bravo", "", false)
+
+	t.Run("built-in default order", func(t *testing.T) {
+		assert.Equal(t, []int{charlie, alpha, bravo}, listedTaskIDs(t, runCommand(t, "list")))
+	})
+
+	t.Run("sort flag", func(t *testing.T) {
+		assert.Equal(t, []int{alpha, bravo, charlie}, listedTaskIDs(t, runCommand(t, "list", "--sort", "title")))
+		assert.Equal(t, []int{charlie, bravo, alpha}, listedTaskIDs(t, runCommand(t, "list", "--sort", "title", "--reverse")))
+	})
+
+	t.Run("config default sort applies without a flag", func(t *testing.T) {
+		writeTestConfig(t, `{"defaults": {"list": {"sort": "title", "reverse": "true"}}}`)
+
+		assert.Equal(t, []int{charlie, bravo, alpha}, listedTaskIDs(t, runCommand(t, "list")))
+	})
+
+	t.Run("flag wins over config", func(t *testing.T) {
+		writeTestConfig(t, `{"defaults": {"list": {"sort": "title"}}}`)
+
+		assert.Equal(t, []int{bravo, alpha, charlie}, listedTaskIDs(t, runCommand(t, "list", "--sort", "id", "--reverse")))
+	})
+
+	t.Run("config default filter", func(t *testing.T) {
+		writeTestConfig(t, `{"defaults": {"list": {"title-prefix": "b"}}}`)
+
+		assert.Equal(t, []int{bravo}, listedTaskIDs(t, runCommand(t, "list")))
+		assert.Equal(t, []int{alpha}, listedTaskIDs(t, runCommand(t, "list", "--title-prefix", "a")))
+	})
+
+	t.Run("invalid sort key is rejected", func(t *testing.T) {
+		output := runCommand(t, "list", "--sort", "title; DROP TABLE tasks")
+		assert.Contains(t, output, "invalid sort key")
+		assert.Equal(t, 3, getTaskCount(t))
+	})
+}
+
+func TestListOverdueDays(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	now := time.Now()
+	daysAgo := func(days int) *time.Time {
+		due := now.AddDate(0, 0, -days).Add(-time.Hour)
+		return &due
+	}
+
+	insertTestTaskWithDueDate(t, "Overdue by 1 day", false, daysAgo(1))
+	tenDays := insertTestTaskWithDueDate(t, "Overdue by 10 days", false, daysAgo(10))
+	fiveDays := insertTestTaskWithDueDate(t, "Overdue by 5 days", false, daysAgo(5))
+	insertTestTaskWithDueDate(t, "Completed long ago", true, daysAgo(20))
+
+	output := runCommand(t, "list", "--overdue-days", "5")
+	// Sorted by due date ascending, most overdue first
+	assert.Equal(t, []int{tenDays, fiveDays}, listedTaskIDs(t, output))
+
+	output = runCommand(t, "list", "--overdue-days", "5", "--sort", "id")
+	assert.Equal(t, []int{tenDays, fiveDays}, listedTaskIDs(t, output))
+
+	assert.Len(t, listedTaskIDs(t, runCommand(t, "list", "--overdue-days", "0")), 3)
+}
+
+func TestListModifiedSince(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	longAgo := time.Now().AddDate(0, 0, -10)
+	insertStale := func(title string) int {
+		id := insertTestTask(t, title, "", false)
+		_, err := database.GetDB().Exec(`UPDATE tasks SET created_at = ?, updated_at = ? WHERE id = ?`, longAgo, longAgo, id)
+		require.NoError(t, err)
+		return id
+	}
+	updatedAt := func(id int) time.Time {
+		var updated time.Time
+		err := database.GetDB().QueryRow(`SELECT updated_at FROM tasks WHERE id = ?`, id).Scan(&updated)
+		require.NoError(t, err)
+		return updated
+	}
+
+	edited := insertStale("Edited task")
+	completed := insertStale("Completed task")
+	insertStale("Untouched task")
+
+	runCommand(t, "edit", fmt.Sprint(edited), "--description", "New details")
+	assert.True(t, updatedAt(edited).After(longAgo.Add(time.Hour)), "edit should bump updated_at")
+
+	runCommand(t, "done", fmt.Sprint(completed))
+	assert.True(t, updatedAt(completed).After(longAgo.Add(time.Hour)), "done should bump updated_at")
+
+	runCommand(t, "add", "New task")
+
+	output := runCommand(t, "list", "--modified-since", "1h")
+	assert.Contains(t, output, "Edited task")
+	assert.Contains(t, output, "Completed task")
+	assert.Contains(t, output, "New task")
+	assert.NotContains(t, output, "Untouched task")
+
+	output = runCommand(t, "list", "--modified-since", longAgo.AddDate(0, 0, -1).Format("2006-01-02"))
+	assert.Len(t, listedTaskIDs(t, output), 4)
+
+	output = runCommand(t, "list", "--modified-since", "yesterday-ish")
+	assert.Contains(t, output, "Error listing tasks: invalid time")
+}
+
+func TestListSortCompletedNulls(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+	createdAt := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	earlier := createdAt.Add(time.Hour)
+	later := createdAt.Add(2 * time.Hour)
+
+	pendingA := insertTestTaskWithSpecificTime(t, "Pending A", "", false, createdAt, nil)
+	completedLate := insertTestTaskWithSpecificTime(t, "Completed late", "", true, createdAt, &later)
+	completedEarly := insertTestTaskWithSpecificTime(t, "Completed early", "", true, createdAt, &earlier)
+	pendingB := insertTestTaskWithSpecificTime(t, "Pending B", "", false, createdAt, nil)
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected []int
+	}{
+		{"pending last by default", nil, []int{completedEarly, completedLate, pendingA, pendingB}},
+		{"nulls last", []string{"--nulls", "last"}, []int{completedEarly, completedLate, pendingA, pendingB}},
+		{"nulls first", []string{"--nulls", "first"}, []int{pendingA, pendingB, completedEarly, completedLate}},
+		{"reverse keeps pending last", []string{"--reverse"}, []int{completedLate, completedEarly, pendingA, pendingB}},
+		{"reverse with nulls first", []string{"--reverse", "--nulls", "first"}, []int{pendingA, pendingB, completedLate, completedEarly}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"list", "--sort", "completed"}, tt.args...)
+			assert.Equal(t, tt.expected, listedTaskIDs(t, runCommand(t, args...)))
+		})
+	}
+
+	t.Run("invalid placement", func(t *testing.T) {
+		output := runCommand(t, "list", "--sort", "completed", "--nulls", "middle")
+		assert.Contains(t, output, `invalid nulls placement "middle"`)
+	})
+}
+
+func TestListNewestDefaultAndLimit(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+	base := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	var ids []int
+	for i := 0; i < 25; i++ {
+		ids = append(ids, insertTestTaskWithSpecificTime(t, fmt.Sprintf("Task %d", i+1), "", false, base.Add(time.Duration(i)*time.Minute), nil))
+	}
+	newestFirst := make([]int, len(ids))
+	for i, id := range ids {
+		newestFirst[len(ids)-1-i] = id
+	}
+
+	t.Run("piped output lists everything oldest first", func(t *testing.T) {
+		output := runCommand(t, "list")
+		assert.Equal(t, ids, listedTaskIDs(t, output))
+		assert.NotContains(t, output, "Showing")
+	})
+
+	t.Run("terminal", func(t *testing.T) {
+		previous := cmd.SetStdoutTerminal(func() bool { return true })
+		defer cmd.SetStdoutTerminal(previous)
+
+		output := runCommand(t, "list")
+		assert.Equal(t, newestFirst[:20], listedTaskIDs(t, output))
+		assert.Contains(t, output, "Showing 20 of 25 tasks (use --all to see everything)")
+
+		output = runCommand(t, "list", "--all")
+		assert.Equal(t, newestFirst, listedTaskIDs(t, output))
+		assert.NotContains(t, output, "Showing")
+
+		output = runCommand(t, "list", "--limit", "0")
+		assert.Equal(t, newestFirst, listedTaskIDs(t, output))
+
+		output = runCommand(t, "list", "--limit", "3")
+		assert.Equal(t, newestFirst[:3], listedTaskIDs(t, output))
+		assert.Contains(t, output, "Showing 3 of 25 tasks")
+
+		output = runCommand(t, "list", "--sort", "id")
+		assert.Equal(t, ids, listedTaskIDs(t, output), "an explicit sort restores the full listing")
+	})
+
+	t.Run("explicit limit applies to piped output", func(t *testing.T) {
+		output := runCommand(t, "list", "--limit", "2")
+		assert.Equal(t, ids[:2], listedTaskIDs(t, output))
+	})
+}
+
+func TestListJSON(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+	pending := insertTestTask(t, "Pending task", "", false)
+	completedAt := time.Date(2024, 5, 2, 10, 0, 0, 0, time.UTC)
+	completed := insertTestTaskWithSpecificTime(t, "Completed task", "With details", true, completedAt.Add(-time.Hour), &completedAt)
+
+	t.Run("compact by default", func(t *testing.T) {
+		output := runCommand(t, "list", "--json", "--sort", "id")
+		assert.Equal(t, 1, strings.Count(output, "\n"), "a compact array is a single line")
+
+		var tasks []models.Task
+		require.NoError(t, json.Unmarshal([]byte(output), &tasks))
+		require.Len(t, tasks, 2)
+		assert.Equal(t, pending, tasks[0].ID)
+		assert.Equal(t, completed, tasks[1].ID)
+	})
+
+	t.Run("indented", func(t *testing.T) {
+		output := runCommand(t, "list", "--json", "--indent", "--sort", "id")
+		assert.Greater(t, strings.Count(output, "\n"), 2)
+		assert.Contains(t, output, "\n  {\n    \"id\": ")
+
+		var tasks []models.Task
+		require.NoError(t, json.Unmarshal([]byte(output), &tasks))
+		assert.Len(t, tasks, 2)
+	})
+
+	t.Run("omit empty fields", func(t *testing.T) {
+		output := runCommand(t, "list", "--json", "--omit-empty-fields", "--sort", "id")
+
+		var objects []map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(output), &objects))
+		require.Len(t, objects, 2)
+
+		assert.NotContains(t, objects[0], "description")
+		assert.NotContains(t, objects[0], "completed_at")
+		assert.Equal(t, "Pending task", objects[0]["title"])
+		assert.Equal(t, false, objects[0]["done"])
+
+		assert.Equal(t, "With details", objects[1]["description"])
+		assert.Contains(t, objects[1], "completed_at")
+
+		// Without the option the empty description is kept
+		output = runCommand(t, "list", "--json", "--sort", "id")
+		require.NoError(t, json.Unmarshal([]byte(output), &objects))
+		assert.Contains(t, objects[0], "description")
+	})
+
+	t.Run("omit empty fields in json lines", func(t *testing.T) {
+		output := runCommand(t, "list", "--jsonl", "--omit-empty-fields", "--sort", "id")
+		lines := strings.Split(strings.TrimSpace(output), "\n")
+		require.Len(t, lines, 2)
+		assert.NotContains(t, lines[0], `"description"`)
+	})
+
+	t.Run("indent requires json", func(t *testing.T) {
+		output := runCommand(t, "list", "--jsonl", "--indent")
+		assert.Contains(t, output, "--indent requires --json")
+	})
+
+	t.Run("empty result is an empty array", func(t *testing.T) {
+		output := runCommand(t, "list", "--json", "--title-contains", "nothing matches")
+		assert.Equal(t, "[]\n", output)
+	})
+}
+
+func TestListPriorityFilter(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+	runCommand(t, "add", "Fix outage", "--priority", "high")
+	runCommand(t, "add", "Review PR", "--priority", "medium")
+	runCommand(t, "add", "Water plants", "--priority", "low")
+	runCommand(t, "add", "Ship release", "--priority", "high")
+
+	t.Run("single priority", func(t *testing.T) {
+		output := runCommand(t, "list", "--priority", "high")
+		assert.Contains(t, output, "Fix outage")
+		assert.Contains(t, output, "Ship release")
+		assert.NotContains(t, output, "Review PR")
+		assert.NotContains(t, output, "Water plants")
+	})
+
+	t.Run("several priorities are a union", func(t *testing.T) {
+		output := runCommand(t, "list", "--priority", "high", "--priority", "Low")
+		assert.Contains(t, output, "Fix outage")
+		assert.Contains(t, output, "Ship release")
+		assert.Contains(t, output, "Water plants")
+		assert.NotContains(t, output, "Review PR")
+
+		assert.Equal(t, "2\n", runCommand(t, "list", "--priority", "medium,low", "--count"))
+	})
+
+	t.Run("combined with other filters", func(t *testing.T) {
+		assert.Equal(t, "1\n", runCommand(t, "list", "--priority", "high", "--title-prefix", "ship", "--count"))
+	})
+
+	t.Run("invalid priority", func(t *testing.T) {
+		output := runCommand(t, "list", "--priority", "urgent")
+		assert.Contains(t, output, `Error listing tasks: invalid priority "urgent"`)
+	})
+}
+
+func TestListTagFilter(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+	runCommand(t, "add", "Fix login bug", "--tag", "work,urgent")
+	runCommand(t, "add", "Write report", "--tag", "work")
+	runCommand(t, "add", "Call plumber", "--tag", "home,urgent")
+	runCommand(t, "add", "Untagged task")
+
+	t.Run("any tag matches by default", func(t *testing.T) {
+		output := runCommand(t, "list", "--tag", "work", "--tag", "urgent")
+		assert.Contains(t, output, "Fix login bug")
+		assert.Contains(t, output, "Write report")
+		assert.Contains(t, output, "Call plumber")
+		assert.NotContains(t, output, "Untagged task")
+	})
+
+	t.Run("all tags with --tag-all", func(t *testing.T) {
+		output := runCommand(t, "list", "--tag", "work,urgent", "--tag-all")
+		assert.Contains(t, output, "Fix login bug")
+		assert.NotContains(t, output, "Write report")
+		assert.NotContains(t, output, "Call plumber")
+		assert.NotContains(t, output, "Untagged task")
+	})
+
+	t.Run("counts differ between any and all", func(t *testing.T) {
+		assert.Equal(t, "3\n", runCommand(t, "list", "--tag", "work,urgent", "--count"))
+		assert.Equal(t, "1\n", runCommand(t, "list", "--tag", "work,urgent", "--tag-all", "--count"))
+		assert.Equal(t, "1\n", runCommand(t, "list", "--tag", "#Home", "--count"))
+	})
+
+	t.Run("repeated tags are counted once", func(t *testing.T) {
+		assert.Equal(t, "2\n", runCommand(t, "list", "--tag", "work,work", "--tag-all", "--count"))
+	})
+
+	t.Run("tag-all requires tag", func(t *testing.T) {
+		output := runCommand(t, "list", "--tag-all")
+		assert.Contains(t, output, "Error listing tasks: --tag-all requires --tag")
+	})
+}
+
+func TestListTagIcons(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+	writeTestConfig(t, `{"tag_icons": {"work": "💼", "home": "🏠"}}`)
+	runCommand(t, "add", "Fix login bug", "--tag", "work")
+	runCommand(t, "add", "Fix the fence", "--tag", "work,home")
+	runCommand(t, "add", "Read a book", "--tag", "leisure")
+	runCommand(t, "add", "Untagged task")
+	ids := listedTaskIDs(t, runCommand(t, "list", "--sort", "id"))
+	require.Len(t, ids, 4)
+	work, both, unmapped, untagged := ids[0], ids[1], ids[2], ids[3]
+
+	t.Run("mapped tags prefix the title", func(t *testing.T) {
+		output := runCommand(t, "list")
+		assert.Contains(t, output, fmt.Sprintf("❌ %d - 💼 Fix login bug\n", work))
+		assert.Contains(t, output, fmt.Sprintf("❌ %d - 🏠💼 Fix the fence\n", both))
+		assert.Equal(t, ids, listedTaskIDs(t, output))
+	})
+
+	t.Run("unmapped and missing tags are not decorated", func(t *testing.T) {
+		output := runCommand(t, "list")
+		assert.Contains(t, output, fmt.Sprintf("❌ %d - Read a book\n", unmapped))
+		assert.Contains(t, output, fmt.Sprintf("❌ %d - Untagged task\n", untagged))
+	})
+
+	t.Run("ascii drops the icons", func(t *testing.T) {
+		output := runCommand(t, "list", "--ascii")
+		assert.Contains(t, output, fmt.Sprintf("[ ] %d - Fix login bug\n", work))
+		assert.NotContains(t, output, "💼")
+		assert.NotContains(t, output, "❌")
+	})
+
+	t.Run("NO_COLOR drops the icons", func(t *testing.T) {
+		t.Setenv("NO_COLOR", "1")
+		output := runCommand(t, "list")
+		assert.Contains(t, output, fmt.Sprintf("❌ %d - Fix login bug\n", work))
+		assert.NotContains(t, output, "💼")
+	})
+}
+
+func TestListInconsistent(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+	completed := time.Now().Add(-time.Hour)
+	insertTestTaskWithSpecificTime(t, "Done properly", "", true, time.Now().Add(-2*time.Hour), &completed)
+	insertTestTask(t, "Pending properly", "", false)
+	doneWithoutTime := insertTestTask(t, "Done without time", "", true)
+	pendingWithTime := insertTestTask(t, "Pending with time", "", false)
+	_, err := database.GetDB().Exec(`UPDATE tasks SET completed_at = ? WHERE id = ?`, completed, pendingWithTime)
+	require.NoError(t, err)
+
+	output := runCommand(t, "list", "--inconsistent")
+	assert.Equal(t, []int{doneWithoutTime, pendingWithTime}, listedTaskIDs(t, output))
+	assert.Equal(t, "2\n", runCommand(t, "list", "--inconsistent", "--count"))
+	assert.Equal(t, "1\n", runCommand(t, "list", "--inconsistent", "--done-count"))
+
+	t.Run("nothing to report", func(t *testing.T) {
+		_, err := database.GetDB().Exec(`DELETE FROM tasks WHERE id IN (?, ?)`, doneWithoutTime, pendingWithTime)
+		require.NoError(t, err)
+		assert.Contains(t, runCommand(t, "list", "--inconsistent"), "No tasks found")
+	})
+}
+
+func TestListSortMultipleKeys(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+	createdAt := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	soon := time.Date(2024, 6, 1, 17, 0, 0, 0, time.UTC)
+	later := time.Date(2024, 6, 10, 17, 0, 0, 0, time.UTC)
+
+	insert := func(title, priority string, due *time.Time) int {
+		id := insertTestTaskWithSpecificTime(t, title, "", false, createdAt, nil)
+		_, err := database.GetDB().Exec(`UPDATE tasks SET priority = ?, due_date = ? WHERE id = ?`, priority, due, id)
+		require.NoError(t, err)
+		return id
+	}
+	highLater := insert("High later", "high", &later)
+	lowSoon := insert("Low soon", "low", &soon)
+	highNone := insert("High undated", "high", nil)
+	highSoon := insert("High soon", "high", &soon)
+	lowLater := insert("Low later", "low", &later)
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected []int
+	}{
+		{"ties on the first key ordered by the second", []string{"--sort", "priority,due"}, []int{lowSoon, lowLater, highSoon, highLater, highNone}},
+		{"keys apply in the given order", []string{"--sort", "due,priority"}, []int{lowSoon, highSoon, lowLater, highLater, highNone}},
+		{"reverse applies to every key", []string{"--sort", "priority,due", "--reverse"}, []int{highLater, highSoon, highNone, lowLater, lowSoon}},
+		{"nulls first", []string{"--sort", "priority,due", "--nulls", "first"}, []int{lowSoon, lowLater, highNone, highSoon, highLater}},
+		{"spaces around keys", []string{"--sort", "priority, due"}, []int{lowSoon, lowLater, highSoon, highLater, highNone}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, listedTaskIDs(t, runCommand(t, append([]string{"list"}, tt.args...)...)))
+		})
+	}
+
+	t.Run("every key is validated", func(t *testing.T) {
+		output := runCommand(t, "list", "--sort", "priority,size")
+		assert.Contains(t, output, `Error listing tasks: invalid sort key "size"`)
+
+		output = runCommand(t, "list", "--sort", "priority,")
+		assert.Contains(t, output, `Error listing tasks: invalid sort key ""`)
+	})
+}
+
+func TestListMaxDescWidth(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+	insertTestTask(t, "Long", "Migrate the billing service to the new cluster", false)
+	insertTestTask(t, "Accented", "Revisão completa", false)
+	insertTestTask(t, "Short", "Quick fix", false)
+
+	t.Run("long descriptions are cut with an ellipsis", func(t *testing.T) {
+		output := runCommand(t, "list", "--max-desc-width", "12")
+		assert.Contains(t, output, "Description: Migrate the…\n")
+		assert.NotContains(t, output, "billing")
+	})
+
+	t.Run("width counts characters, not bytes", func(t *testing.T) {
+		output := runCommand(t, "list", "--max-desc-width", "8")
+		assert.Contains(t, output, "Description: Revisão…\n")
+	})
+
+	t.Run("short descriptions are untouched", func(t *testing.T) {
+		output := runCommand(t, "list", "--max-desc-width", "12")
+		assert.Contains(t, output, "Description: Quick fix\n")
+	})
+
+	t.Run("zero keeps full descriptions", func(t *testing.T) {
+		output := runCommand(t, "list", "--max-desc-width", "0")
+		assert.Contains(t, output, "Description: Migrate the billing service to the new cluster\n")
+	})
+
+	t.Run("negative width is rejected", func(t *testing.T) {
+		output := runCommand(t, "list", "--max-desc-width", "-1")
+		assert.Contains(t, output, "Error listing tasks: --max-desc-width cannot be negative")
+	})
+}
+
+func TestListFormat(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+	runCommand(t, "add", "Fix login bug", "--tag", "work", "-d", "Crash, then retry")
+	runCommand(t, "add", "Water plants", "--tag", "home")
+	runCommand(t, "add", "Write report", "--tag", "work")
+	ids := listedTaskIDs(t, runCommand(t, "list", "--sort", "id"))
+	require.Len(t, ids, 3)
+
+	t.Run("csv has a header and only the filtered tasks", func(t *testing.T) {
+		output := runCommand(t, "list", "--format", "csv", "--tag", "work", "--sort", "title", "--reverse")
+
+		records, err := csv.NewReader(strings.NewReader(output)).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 3)
+		assert.Equal(t, []string{"ID", "Title", "Description", "Done", "Created At", "Completed At", "UUID"}, records[0])
+		assert.Equal(t, []string{fmt.Sprint(ids[2]), "Write report", "", "false"}, records[1][:4])
+		assert.Equal(t, []string{fmt.Sprint(ids[0]), "Fix login bug", "Crash, then retry", "false"}, records[2][:4])
+	})
+
+	t.Run("csv of no tasks is only the header", func(t *testing.T) {
+		output := runCommand(t, "list", "--format", "csv", "--tag", "leisure")
+		assert.Equal(t, "ID,Title,Description,Done,Created At,Completed At,UUID\n", output)
+	})
+
+	t.Run("json prints the filtered tasks as an array", func(t *testing.T) {
+		output := runCommand(t, "list", "--format", "json", "--tag", "home")
+
+		var tasks []struct {
+			ID    int    `json:"id"`
+			Title string `json:"title"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(output), &tasks))
+		require.Len(t, tasks, 1)
+		assert.Equal(t, ids[1], tasks[0].ID)
+		assert.Equal(t, "Water plants", tasks[0].Title)
+	})
+
+	t.Run("invalid and conflicting formats are rejected", func(t *testing.T) {
+		output := runCommand(t, "list", "--format", "xml")
+		assert.Contains(t, output, `Error listing tasks: invalid --format "xml" (use text, csv or json)`)
+
+		output = runCommand(t, "list", "--format", "csv", "--jsonl")
+		assert.Contains(t, output, "Error listing tasks: use either --format or --json/--jsonl, not both")
+	})
+}
+
+func TestListStatus(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+	pending1 := insertTestTask(t, "Write tests", "", false)
+	done1 := insertTestTask(t, "Ship release", "", true)
+	pending2 := insertTestTask(t, "Update docs", "", false)
+	done2 := insertTestTask(t, "Fix build", "", true)
+
+	t.Run("done lists only completed tasks", func(t *testing.T) {
+		output := runCommand(t, "list", "--status", "done", "--sort", "id")
+		assert.Equal(t, []int{done1, done2}, listedTaskIDs(t, output))
+	})
+
+	t.Run("pending lists only open tasks", func(t *testing.T) {
+		output := runCommand(t, "list", "--status", "Pending", "--sort", "id")
+		assert.Equal(t, []int{pending1, pending2}, listedTaskIDs(t, output))
+	})
+
+	t.Run("all is the default", func(t *testing.T) {
+		all := []int{pending1, done1, pending2, done2}
+		assert.Equal(t, all, listedTaskIDs(t, runCommand(t, "list", "--status", "all", "--sort", "id")))
+		assert.Equal(t, all, listedTaskIDs(t, runCommand(t, "list", "--sort", "id")))
+	})
+
+	t.Run("combines with other filters and counts", func(t *testing.T) {
+		output := runCommand(t, "list", "--status", "pending", "--title-contains", "docs")
+		assert.Equal(t, []int{pending2}, listedTaskIDs(t, output))
+
+		output = runCommand(t, "list", "--status", "done", "--count")
+		assert.Equal(t, "2\n", output)
+	})
+
+	t.Run("invalid status lists the accepted values", func(t *testing.T) {
+		output := runCommand(t, "list", "--status", "finished")
+		assert.Contains(t, output, `Error listing tasks: invalid status "finished" (valid: all, done, pending)`)
+
+		output = runCommand(t, "list", "--status", "done", "--pending-count")
+		assert.Contains(t, output, "Error listing tasks: use either --status or --done-count/--pending-count, not both")
+	})
+}
+
+func TestListOverdueMarker(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+	runCommand(t, "add", "Pay rent", "--due", "2000-01-01")
+	runCommand(t, "add", "Renew passport", "--due", time.Now().AddDate(0, 1, 0).Format("2006-01-02"))
+	runCommand(t, "add", "Old report", "--due", "2000-01-01 09:00", "--done")
+
+	t.Run("pending tasks past due are flagged", func(t *testing.T) {
+		output := runCommand(t, "list", "--sort", "id")
+		assert.Contains(t, output, "Due: 2000-01-01 23:59 ⚠️ OVERDUE\n")
+		assert.Equal(t, 1, strings.Count(output, "OVERDUE"), "only the pending past-due task is flagged")
+	})
+
+	t.Run("ascii drops the emoji", func(t *testing.T) {
+		output := runCommand(t, "list", "--ascii")
+		assert.Contains(t, output, "Due: 2000-01-01 23:59 OVERDUE\n")
+		assert.NotContains(t, output, "⚠️")
+	})
+}
+
+func TestListCompletedInMonth(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+	complete := func(title string, completedAt time.Time) int {
+		return insertTestTaskWithSpecificTime(t, title, "", true, completedAt.Add(-time.Hour), &completedAt)
+	}
+	lateDecember := complete("Year-end review", time.Date(2023, 12, 31, 23, 59, 0, 0, time.Local))
+	newYear := complete("New year plan", time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local))
+	endOfJanuary := complete("Close January", time.Date(2024, 1, 31, 23, 30, 0, 0, time.Local))
+	february := complete("February report", time.Date(2024, 2, 1, 0, 30, 0, 0, time.Local))
+	insertTestTask(t, "Still pending", "", false)
+
+	t.Run("month selects completions inside it", func(t *testing.T) {
+		output := runCommand(t, "list", "--month", "2024-01", "--sort", "id")
+		assert.Equal(t, []int{newYear, endOfJanuary}, listedTaskIDs(t, output))
+	})
+
+	t.Run("december does not spill into the next year", func(t *testing.T) {
+		output := runCommand(t, "list", "--month", "2023-12", "--sort", "id")
+		assert.Equal(t, []int{lateDecember}, listedTaskIDs(t, output))
+
+		output = runCommand(t, "list", "--month", "2024-02", "--sort", "id")
+		assert.Equal(t, []int{february}, listedTaskIDs(t, output))
+	})
+
+	t.Run("this month", func(t *testing.T) {
+		now := time.Now()
+		start, _ := dates.MonthBounds(now)
+		thisMonth := complete("Done this month", start)
+		complete("Done last month", start.Add(-time.Minute))
+
+		output := runCommand(t, "list", "--completed-this-month")
+		assert.Equal(t, []int{thisMonth}, listedTaskIDs(t, output))
+	})
+
+	t.Run("invalid month", func(t *testing.T) {
+		output := runCommand(t, "list", "--month", "2024-13")
+		assert.Contains(t, output, `Error listing tasks: invalid month "2024-13", expected YYYY-MM`)
+	})
+}
+
+func TestListSortSameCompletionTime(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+	batch := time.Date(2024, 5, 2, 9, 0, 0, 0, time.UTC)
+	earlier := batch.Add(-time.Hour)
+	created := batch.Add(-24 * time.Hour)
+	a := insertTestTaskWithSpecificTime(t, "Batch A", "", true, created, &batch)
+	b := insertTestTaskWithSpecificTime(t, "Batch B", "", true, created, &batch)
+	pending := insertTestTaskWithSpecificTime(t, "Pending", "", false, created, nil)
+	c := insertTestTaskWithSpecificTime(t, "Batch C", "", true, created, &batch)
+	first := insertTestTaskWithSpecificTime(t, "Finished first", "", true, created, &earlier)
+
+	t.Run("ties keep id order", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			output := runCommand(t, "list", "--sort", "completed")
+			assert.Equal(t, []int{first, a, b, c, pending}, listedTaskIDs(t, output))
+		}
+	})
+
+	t.Run("reversed ties still keep id order", func(t *testing.T) {
+		output := runCommand(t, "list", "--sort", "completed", "--reverse")
+		assert.Equal(t, []int{a, b, c, first, pending}, listedTaskIDs(t, output))
+	})
+}
+
+func TestListSortKeysDesc(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+	base := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	at := func(hours int) *time.Time {
+		value := base.Add(time.Duration(hours) * time.Hour)
+		return &value
+	}
+	// Ids, creation times, completion times and titles each order the tasks differently
+	cherry := insertTestTaskWithSpecificTime(t, "Cherry", "", true, *at(2), at(5))
+	apple := insertTestTaskWithSpecificTime(t, "apple", "", false, *at(3), nil)
+	banana := insertTestTaskWithSpecificTime(t, "Banana", "", true, *at(1), at(4))
+
+	tests := []struct {
+		key  string
+		asc  []int
+		desc []int
+	}{
+		{"id", []int{cherry, apple, banana}, []int{banana, apple, cherry}},
+		{"created", []int{banana, cherry, apple}, []int{apple, cherry, banana}},
+		{"title", []int{apple, banana, cherry}, []int{cherry, banana, apple}},
+		// The pending task has no completion time and stays last either way
+		{"completed", []int{banana, cherry, apple}, []int{cherry, banana, apple}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			assert.Equal(t, tt.asc, listedTaskIDs(t, runCommand(t, "list", "--sort", tt.key)))
+			assert.Equal(t, tt.desc, listedTaskIDs(t, runCommand(t, "list", "--sort", tt.key, "--desc")))
+			assert.Equal(t, tt.desc, listedTaskIDs(t, runCommand(t, "list", "--sort", tt.key, "--reverse")))
+		})
+	}
+
+	t.Run("unknown keys are rejected", func(t *testing.T) {
+		output := runCommand(t, "list", "--sort", "title; DROP TABLE tasks", "--desc")
+		assert.Contains(t, output, `Error listing tasks: invalid sort key "title; DROP TABLE tasks"`)
+		assert.Equal(t, 3, getTaskCount(t))
+	})
+}
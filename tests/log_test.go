@@ -0,0 +1,120 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/eduardamirelly/tasker/config"
+	"github.com/eduardamirelly/tasker/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogFormatJSON(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+
+	// decode parses the log lines of output, skipping task details
+	decode := func(t *testing.T, output string) []map[string]interface{} {
+		var entries []map[string]interface{}
+		for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+			if !strings.HasPrefix(line, "{") {
+				continue
+			}
+			var entry map[string]interface{}
+			require.NoError(t, json.Unmarshal([]byte(line), &entry), "line %q", line)
+			entries = append(entries, entry)
+		}
+		return entries
+	}
+
+	t.Run("add", func(t *testing.T) {
+		output := runCommand(t, "add", "Write report", "--log-format", "json")
+		entries := decode(t, output)
+		require.Len(t, entries, 1)
+
+		var id int
+		require.NoError(t, database.GetDB().QueryRow(`SELECT id FROM tasks WHERE title = 'Write report'`).Scan(&id))
+		assert.Equal(t, "INFO", entries[0]["level"])
+		assert.Equal(t, "Task added: Write report", entries[0]["msg"])
+		assert.Equal(t, float64(id), entries[0]["task_id"])
+		assert.Contains(t, entries[0], "time")
+	})
+
+	t.Run("warnings", func(t *testing.T) {
+		output := runCommand(t, "add", "Write report", "--log-format", "json")
+		entries := decode(t, output)
+		require.Len(t, entries, 2)
+		assert.Equal(t, "WARN", entries[0]["level"])
+		assert.Equal(t, `a task titled "Write report" already exists`, entries[0]["msg"])
+		assert.Equal(t, "INFO", entries[1]["level"])
+	})
+
+	t.Run("done", func(t *testing.T) {
+		id := insertTestTask(t, "Review PR", "", false)
+		entries := decode(t, runCommand(t, "done", fmt.Sprint(id), "--log-format", "json"))
+		require.Len(t, entries, 1)
+		assert.Equal(t, "Task marked as done: Review PR", entries[0]["msg"])
+		assert.Equal(t, float64(id), entries[0]["task_id"])
+	})
+
+	t.Run("export", func(t *testing.T) {
+		id := insertTestTask(t, "Exported task", "", false)
+		path := filepath.Join(t.TempDir(), "tasks.csv")
+		entries := decode(t, runCommand(t, "export", fmt.Sprint(id), "9999", "-o", path, "--log-format", "json"))
+		require.Len(t, entries, 2)
+		assert.Equal(t, "WARN", entries[0]["level"])
+		assert.Equal(t, "task not found, skipping: 9999", entries[0]["msg"])
+		assert.Equal(t, "INFO", entries[1]["level"])
+		assert.Equal(t, "Tasks exported successfully to "+path, entries[1]["msg"])
+	})
+
+	t.Run("import", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "tasks.csv")
+		content := "ID,Title,Description,Done,Created At,Completed At\n" +
+			"700,Imported task,,false,2024-05-01 10:00:00,\n" +
+			"701,Bad row,,maybe,2024-05-01 10:00:00,\n"
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+		output := runCommand(t, "import", path, "--skip-bad-rows", "--log-format", "json")
+		entries := decode(t, output)
+		require.Len(t, entries, 2, output)
+		assert.Equal(t, "WARN", entries[0]["level"])
+		assert.True(t, strings.HasPrefix(fmt.Sprint(entries[0]["msg"]), `line 3: invalid done value "maybe"`), entries[0]["msg"])
+		assert.Equal(t, "INFO", entries[1]["level"])
+		assert.Equal(t, "Import finished. added: 1, overwritten: 0, skipped: 0, invalid: 1 (lines 3)", entries[1]["msg"])
+	})
+
+	t.Run("goal and alias", func(t *testing.T) {
+		t.Setenv(config.PathEnv, filepath.Join(t.TempDir(), "config.json"))
+		entries := decode(t, runCommand(t, "goal", "set", "3", "--per", "week", "--log-format", "json"))
+		require.Len(t, entries, 1)
+		assert.Equal(t, "Goal set: 3 task(s) per week", entries[0]["msg"])
+
+		entries = decode(t, runCommand(t, "alias", "save", "mine", "list --count", "--log-format", "json"))
+		require.Len(t, entries, 1)
+		assert.Equal(t, "Alias saved: mine = list --count", entries[0]["msg"])
+	})
+
+	t.Run("text is the default", func(t *testing.T) {
+		assert.Equal(t, "✓ Task added: Plan sprint\n", runCommand(t, "add", "Plan sprint"))
+	})
+
+	t.Run("skip warnings honor --strict", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "strict.csv")
+		output := runCommand(t, "export", "9999", "-o", path, "--strict")
+		assert.Contains(t, output, "Error exporting tasks: task not found, skipping: 9999 (--strict)")
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		_, err := runCommandErr(t, "add", "Anything", "--log-format", "xml")
+		assert.ErrorContains(t, err, `invalid --log-format "xml"`)
+	})
+}
@@ -0,0 +1,164 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/eduardamirelly/tasker/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskCSVRecordRoundTrip(t *testing.T) {
+	createdAt := time.Date(2024, 5, 1, 8, 15, 0, 0, time.UTC)
+	completedAt := time.Date(2024, 5, 2, 17, 45, 30, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		task models.Task
+	}{
+		{
+			name: "completed task",
+			task: models.Task{
+				ID:          7,
+				Title:       "Write report",
+				Description: "Quarterly, with \"quotes\"",
+				Done:        true,
+				CreatedAt:   createdAt,
+				CompletedAt: &completedAt,
+			},
+		},
+		{
+			name: "task with a uuid",
+			task: models.Task{
+				ID:        9,
+				UUID:      "0b6a3f0e-5d1c-4a8e-9f2b-7c4d1e6a8b3f",
+				Title:     "Stable id",
+				CreatedAt: createdAt,
+			},
+		},
+		{
+			name: "pending task with nil completed_at",
+			task: models.Task{
+				ID:        8,
+				Title:     "Unicode Task 🚀",
+				CreatedAt: createdAt,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := tt.task.ToCSVRecord()
+			require.Len(t, record, len(models.CSVHeader))
+
+			task, err := models.TaskFromCSVRecord(record)
+			require.NoError(t, err)
+			assert.Equal(t, tt.task, task)
+		})
+	}
+
+	t.Run("nil completed_at becomes an empty field", func(t *testing.T) {
+		record := models.Task{ID: 1, Title: "Pending", CreatedAt: createdAt}.ToCSVRecord()
+		assert.Equal(t, []string{"1", "Pending", "", "false", "2024-05-01 08:15:00", "", ""}, record)
+	})
+}
+
+func TestTaskFromCSVRecordWithoutUUID(t *testing.T) {
+	task, err := models.TaskFromCSVRecord([]string{"1", "Legacy", "", "false", "2024-05-01 08:15:00", ""})
+	require.NoError(t, err)
+	assert.Equal(t, "Legacy", task.Title)
+	assert.Empty(t, task.UUID)
+
+	task, err = models.TaskFromCSVRecord([]string{"1", "Upper", "", "false", "2024-05-01 08:15:00", "", " 0B6A3F0E-5D1C-4A8E-9F2B-7C4D1E6A8B3F "})
+	require.NoError(t, err)
+	assert.Equal(t, "0b6a3f0e-5d1c-4a8e-9f2b-7c4d1e6a8b3f", task.UUID)
+}
+
+func TestTaskCSVRecordPrecision(t *testing.T) {
+	createdAt := time.Date(2024, 5, 1, 8, 15, 0, 123456789, time.UTC)
+	task := models.Task{ID: 1, Title: "Precise", CreatedAt: createdAt}
+
+	record := task.ToCSVRecordWithLayout(models.CSVPreciseTimeLayout)
+	assert.Equal(t, "2024-05-01T08:15:00.123456789Z", record[4])
+
+	parsed, err := models.TaskFromCSVRecord(record)
+	require.NoError(t, err)
+	assert.True(t, createdAt.Equal(parsed.CreatedAt))
+
+	// The default layout still parses, at second precision
+	parsed, err = models.TaskFromCSVRecord(task.ToCSVRecord())
+	require.NoError(t, err)
+	assert.True(t, createdAt.Truncate(time.Second).Equal(parsed.CreatedAt))
+}
+
+func TestTaskFromCSVRecordErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		record []string
+		errMsg string
+	}{
+		{
+			name:   "wrong column count",
+			record: []string{"1", "Title"},
+			errMsg: "expected 6 or 7 columns",
+		},
+		{
+			name:   "invalid uuid",
+			record: []string{"1", "Title", "", "false", "2024-05-01 08:15:00", "", "1234"},
+			errMsg: "invalid uuid",
+		},
+		{
+			name:   "invalid id",
+			record: []string{"abc", "Title", "", "false", "2024-05-01 08:15:00", ""},
+			errMsg: "invalid id",
+		},
+		{
+			name:   "invalid done value",
+			record: []string{"1", "Title", "", "maybe", "2024-05-01 08:15:00", ""},
+			errMsg: "invalid done value",
+		},
+		{
+			name:   "invalid created at",
+			record: []string{"1", "Title", "", "false", "yesterday", ""},
+			errMsg: "invalid created at",
+		},
+		{
+			name:   "invalid completed at",
+			record: []string{"1", "Title", "", "true", "2024-05-01 08:15:00", "soon"},
+			errMsg: "invalid completed at",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := models.TaskFromCSVRecord(tt.record)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestTaskIsOverdue(t *testing.T) {
+	now := time.Date(2024, 6, 10, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Minute)
+
+	tests := []struct {
+		name string
+		task models.Task
+		want bool
+	}{
+		{"pending past due", models.Task{DueDate: &past}, true},
+		{"pending due later", models.Task{DueDate: &future}, false},
+		{"pending due right now", models.Task{DueDate: &now}, false},
+		{"done past due", models.Task{Done: true, DueDate: &past}, false},
+		{"no due date", models.Task{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.task.IsOverdue(now))
+		})
+	}
+}
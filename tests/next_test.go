@@ -0,0 +1,107 @@
+package tests
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eduardamirelly/tasker/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNext(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+	oldLow := insertTestTask(t, "Old low priority", "", false)
+	high := insertTestTask(t, "High priority", "", false)
+	medium := insertTestTask(t, "Medium priority", "", false)
+	dueLater := insertTestTask(t, "Due next week", "", false)
+	dueSoon := insertTestTask(t, "Due tomorrow", "", false)
+	insertTestTask(t, "Already done", "", true)
+
+	setPriority := func(id int, priority string) {
+		_, err := database.GetDB().Exec(`UPDATE tasks SET priority = ? WHERE id = ?`, priority, id)
+		require.NoError(t, err)
+	}
+	setDue := func(id int, due time.Time) {
+		_, err := database.GetDB().Exec(`UPDATE tasks SET due_date = ? WHERE id = ?`, due, id)
+		require.NoError(t, err)
+	}
+	setPriority(oldLow, "low")
+	setPriority(high, "high")
+	setPriority(medium, "medium")
+	setDue(dueLater, time.Now().AddDate(0, 0, 7))
+	setDue(dueSoon, time.Now().AddDate(0, 0, 1))
+
+	// Due tasks first, then by priority, then oldest
+	for _, want := range []struct {
+		id    int
+		title string
+	}{
+		{dueSoon, "Due tomorrow"},
+		{dueLater, "Due next week"},
+		{high, "High priority"},
+		{medium, "Medium priority"},
+		{oldLow, "Old low priority"},
+	} {
+		output := runCommand(t, "next")
+		require.True(t, strings.HasPrefix(output, fmt.Sprintf("Next up: %d - %s\n", want.id, want.title)), output)
+
+		_, err := database.GetDB().Exec(`UPDATE tasks SET done = TRUE, completed_at = CURRENT_TIMESTAMP WHERE id = ?`, want.id)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, "No pending tasks, all done!\n", runCommand(t, "next"))
+}
+
+func TestDoneChain(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+	first := insertTestTask(t, "Write draft", "", false)
+	second := insertTestTask(t, "Review draft", "", false)
+	urgent := insertTestTask(t, "Fix outage", "", false)
+	_, err := database.GetDB().Exec(`UPDATE tasks SET priority = 'high' WHERE id = ?`, urgent)
+	require.NoError(t, err)
+
+	t.Run("shows the next best pending task", func(t *testing.T) {
+		output := runCommand(t, "done", fmt.Sprint(first), "--chain")
+		assert.Contains(t, output, "✓ Task marked as done: Write draft")
+		assert.Contains(t, output, fmt.Sprintf("\nNext up: %d - Fix outage\n", urgent))
+		assert.True(t, getTaskByID(t, first).Done)
+	})
+
+	t.Run("keeps going through the list", func(t *testing.T) {
+		output := runCommand(t, "done", fmt.Sprint(urgent), "--chain")
+		assert.Contains(t, output, fmt.Sprintf("Next up: %d - Review draft\n", second))
+
+		output = runCommand(t, "done", fmt.Sprint(second), "--chain")
+		assert.Contains(t, output, "No pending tasks, all done!")
+	})
+
+	t.Run("nothing is chained without the flag", func(t *testing.T) {
+		clearTestTasks(t)
+		id := insertTestTask(t, "Only task", "", false)
+		insertTestTask(t, "Another task", "", false)
+		output := runCommand(t, "done", fmt.Sprint(id))
+		assert.NotContains(t, output, "Next up")
+	})
+
+	t.Run("requires a task id", func(t *testing.T) {
+		output := runCommand(t, "done", "--overdue", "--chain")
+		assert.Contains(t, output, "Error: --chain requires a task id")
+	})
+
+	t.Run("unknown task shows nothing next", func(t *testing.T) {
+		output := runCommand(t, "done", "99999", "--chain")
+		assert.Contains(t, output, "❌ Task not found: 99999")
+		assert.NotContains(t, output, "Next up")
+	})
+}
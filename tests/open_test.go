@@ -0,0 +1,113 @@
+package tests
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/eduardamirelly/tasker/cmd"
+	"github.com/eduardamirelly/tasker/links"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFirstURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		texts    []string
+		expected string
+	}{
+		{
+			name:     "url in title wins",
+			texts:    []string{"Review https://example.com/pr/1", "See http://other.example"},
+			expected: "https://example.com/pr/1",
+		},
+		{
+			name:     "url in description",
+			texts:    []string{"Read the spec", "Spec lives at https://example.com/spec?v=2#intro."},
+			expected: "https://example.com/spec?v=2#intro",
+		},
+		{
+			name:     "url in parentheses",
+			texts:    []string{"Docs (https://example.com/docs)"},
+			expected: "https://example.com/docs",
+		},
+		{
+			name:     "no url",
+			texts:    []string{"Buy groceries", "Milk, eggs, www-ish text"},
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, links.FirstURL(tt.texts...))
+		})
+	}
+}
+
+func TestOpenFirst(t *testing.T) {
+	t.Run("opener is invoked with the url", func(t *testing.T) {
+		var opened []string
+		opener := func(url string) error {
+			opened = append(opened, url)
+			return nil
+		}
+
+		url, err := links.OpenFirst(opener, "Review PR", "https://example.com/pr/42")
+		require.NoError(t, err)
+		assert.Equal(t, "https://example.com/pr/42", url)
+		assert.Equal(t, []string{"https://example.com/pr/42"}, opened)
+	})
+
+	t.Run("no url", func(t *testing.T) {
+		opener := func(url string) error {
+			t.Fatalf("opener should not be called, got %s", url)
+			return nil
+		}
+
+		_, err := links.OpenFirst(opener, "Buy groceries", "")
+		assert.ErrorIs(t, err, links.ErrNoURL)
+	})
+}
+
+func TestOpenCommandWithoutURL(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+	taskID := insertTestTask(t, "Buy groceries", "Milk and eggs", false)
+
+	output := runCommand(t, "open", fmt.Sprint(taskID))
+	assert.Contains(t, output, "No URL found in task")
+
+	output = runCommand(t, "open", "999")
+	assert.Contains(t, output, "Task not found: 999")
+}
+
+func TestOpenCommand(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	var opened []string
+	previous := cmd.SetOpener(func(url string) error {
+		opened = append(opened, url)
+		return nil
+	})
+	defer cmd.SetOpener(previous)
+
+	clearTestTasks(t)
+	taskID := insertTestTask(t, "Review PR", "See https://example.com/search?a=1&b=2|x^y for details.", false)
+
+	output := runCommand(t, "open", fmt.Sprint(taskID))
+	assert.Equal(t, []string{"https://example.com/search?a=1&b=2|x^y"}, opened)
+	assert.Contains(t, output, "Opening https://example.com/search?a=1&b=2|x^y")
+
+	t.Run("opener error is reported", func(t *testing.T) {
+		cmd.SetOpener(func(url string) error { return errors.New("no browser") })
+		output := runCommand(t, "open", fmt.Sprint(taskID))
+		assert.Contains(t, output, "Error opening https://example.com/search?a=1&b=2|x^y: no browser")
+	})
+}
@@ -0,0 +1,77 @@
+package tests
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/eduardamirelly/tasker/progress"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgressShouldShow(t *testing.T) {
+	tests := []struct {
+		name     string
+		total    int
+		isTTY    bool
+		quiet    bool
+		expected bool
+	}{
+		{
+			name:     "large operation on a terminal",
+			total:    progress.Threshold + 1,
+			isTTY:    true,
+			expected: true,
+		},
+		{
+			name:     "small operation on a terminal",
+			total:    progress.Threshold,
+			isTTY:    true,
+			expected: false,
+		},
+		{
+			name:     "large operation piped",
+			total:    progress.Threshold * 2,
+			isTTY:    false,
+			expected: false,
+		},
+		{
+			name:     "large operation in quiet mode",
+			total:    progress.Threshold * 2,
+			isTTY:    true,
+			quiet:    true,
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, progress.ShouldShow(tt.total, tt.isTTY, tt.quiet))
+		})
+	}
+}
+
+func TestProgressReporter(t *testing.T) {
+	t.Run("enabled reporter writes progress", func(t *testing.T) {
+		var out bytes.Buffer
+		reporter := progress.New(&out, "Exporting", 4, true)
+
+		for i := 0; i < 4; i++ {
+			reporter.Increment()
+		}
+		reporter.Done()
+
+		assert.Contains(t, out.String(), "Exporting 1/4 (25%)")
+		assert.Contains(t, out.String(), "Exporting 4/4 (100%)")
+		assert.Equal(t, byte('\n'), out.Bytes()[out.Len()-1])
+	})
+
+	t.Run("disabled reporter writes nothing", func(t *testing.T) {
+		var out bytes.Buffer
+		reporter := progress.New(&out, "Exporting", 4, false)
+
+		reporter.Increment()
+		reporter.Done()
+
+		assert.Empty(t, out.String())
+	})
+}
@@ -0,0 +1,47 @@
+package tests
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/eduardamirelly/tasker/prompt"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfirm(t *testing.T) {
+	t.Run("refuses when not interactive", func(t *testing.T) {
+		var out bytes.Buffer
+		ok, err := prompt.Confirm(strings.NewReader("yes\n"), &out, "Proceed?", false)
+
+		assert.ErrorIs(t, err, prompt.ErrNotInteractive)
+		assert.Contains(t, err.Error(), "--yes")
+		assert.False(t, ok)
+		assert.Empty(t, out.String(), "nothing should be asked without a terminal")
+	})
+
+	tests := []struct {
+		name     string
+		input    string
+		expected bool
+	}{
+		{"yes", "yes\n", true},
+		{"short yes", " Y \n", true},
+		{"yes without newline", "y", true},
+		{"no", "n\n", false},
+		{"empty answer", "\n", false},
+		{"closed input", "", false},
+		{"anything else", "sure\n", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			ok, err := prompt.Confirm(strings.NewReader(tt.input), &out, "Proceed?", true)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, ok)
+			assert.Contains(t, out.String(), "Proceed? [y/N]: ")
+		})
+	}
+}
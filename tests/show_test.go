@@ -0,0 +1,147 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/eduardamirelly/tasker/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShowCompletionLatency(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	createdAt := time.Date(2024, 5, 10, 9, 0, 0, 0, time.UTC)
+
+	t.Run("completed task shows latency", func(t *testing.T) {
+		clearTestTasks(t)
+
+		completedAt := createdAt.Add(51*time.Hour + 20*time.Minute)
+		taskID := insertTestTaskWithSpecificTime(t, "Write report", "", true, createdAt, &completedAt)
+
+		output := runCommand(t, "show", fmt.Sprint(taskID))
+		assert.Contains(t, output, "Completed after: 2d 3h\n")
+	})
+
+	t.Run("short latency", func(t *testing.T) {
+		clearTestTasks(t)
+
+		completedAt := createdAt.Add(45 * time.Minute)
+		taskID := insertTestTaskWithSpecificTime(t, "Quick fix", "", true, createdAt, &completedAt)
+
+		output := runCommand(t, "show", fmt.Sprint(taskID))
+		assert.Contains(t, output, "Completed after: 45m\n")
+	})
+
+	t.Run("pending task has no latency", func(t *testing.T) {
+		clearTestTasks(t)
+
+		taskID := insertTestTaskWithSpecificTime(t, "Pending task", "", false, createdAt, nil)
+
+		output := runCommand(t, "show", fmt.Sprint(taskID))
+		assert.Contains(t, output, "Title: Pending task")
+		assert.NotContains(t, output, "Completed after")
+	})
+}
+
+func TestShowPretty(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+	runCommand(t, "add", "Ship release", "-d", "Tag and publish", "--due", "2030-01-15 09:30", "--tag", "work,release")
+
+	var id int
+	var uuid string
+	require.NoError(t, database.GetDB().QueryRow(`SELECT id, uuid FROM tasks`).Scan(&id, &uuid))
+
+	createdAt := time.Date(2024, 5, 10, 9, 0, 0, 0, time.UTC)
+	_, err := database.GetDB().Exec(`UPDATE tasks SET created_at = ? WHERE id = ?`, createdAt, id)
+	require.NoError(t, err)
+
+	output := runCommand(t, "done", fmt.Sprint(id), "--note", "Shipped", "--pretty")
+	assert.Contains(t, output, "✓ Task marked as done: Ship release")
+	assert.Contains(t, output, fmt.Sprintf("%-18s%s\n", "Status:", "done"))
+
+	// Pin the completion time so the latency is known
+	completedAt := createdAt.Add(51*time.Hour + 20*time.Minute)
+	_, err = database.GetDB().Exec(`UPDATE tasks SET completed_at = ? WHERE id = ?`, completedAt, id)
+	require.NoError(t, err)
+
+	var updatedAt time.Time
+	require.NoError(t, database.GetDB().QueryRow(`SELECT updated_at FROM tasks WHERE id = ?`, id).Scan(&updatedAt))
+
+	line := func(label, value string) string {
+		return fmt.Sprintf("%-18s%s\n", label+":", value)
+	}
+	expected := line("ID", fmt.Sprint(id)) +
+		line("UUID", uuid) +
+		line("Title", "Ship release") +
+		line("Description", "Tag and publish") +
+		line("Status", "done") +
+		line("Priority", "medium") +
+		line("Due", "2030-01-15 09:30") +
+		line("Tags", "release, work") +
+		line("Attachments", "N/A") +
+		line("Created At", "2024-05-10 09:00:00") +
+		line("Completed At", "2024-05-12 12:20:00") +
+		line("Completed after", "2d 3h") +
+		line("Note", "Shipped") +
+		line("Updated At", updatedAt.Format("2006-01-02 15:04:05"))
+
+	assert.Equal(t, expected, runCommand(t, "show", fmt.Sprint(id), "--pretty"))
+
+	t.Run("missing values are N/A", func(t *testing.T) {
+		pendingID := insertTestTask(t, "Bare task", "", false)
+		output := runCommand(t, "show", fmt.Sprint(pendingID), "--pretty")
+		assert.Contains(t, output, line("Status", "pending"))
+		assert.Contains(t, output, line("Due", "N/A"))
+		assert.Contains(t, output, line("Tags", "N/A"))
+		assert.Contains(t, output, line("Completed after", "N/A"))
+	})
+
+	t.Run("default layout is unchanged", func(t *testing.T) {
+		output := runCommand(t, "show", fmt.Sprint(id))
+		assert.Contains(t, output, "Title: Ship release\n")
+		assert.NotContains(t, output, "Status:")
+	})
+}
+
+func TestPrintTaskWithoutCompletionTime(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+	pendingID := insertTestTask(t, "Pending task", "", false)
+	// Done without a completion time, as left by older versions
+	doneID := insertTestTask(t, "Done without time", "", true)
+
+	for _, tt := range []struct {
+		name string
+		args []string
+	}{
+		{"show pending", []string{"show", fmt.Sprint(pendingID)}},
+		{"show done without time", []string{"show", fmt.Sprint(doneID)}},
+		{"done on a task already done without time", []string{"done", fmt.Sprint(doneID)}},
+		{"list", []string{"list"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var output string
+			require.NotPanics(t, func() { output = runCommand(t, tt.args...) })
+			assert.Contains(t, output, "Completed At: N/A\n")
+			assert.NotContains(t, output, "Completed after")
+		})
+	}
+
+	t.Run("pretty layout", func(t *testing.T) {
+		var output string
+		require.NotPanics(t, func() { output = runCommand(t, "show", fmt.Sprint(doneID), "--pretty") })
+		assert.Contains(t, output, "N/A")
+	})
+}
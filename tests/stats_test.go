@@ -0,0 +1,114 @@
+package tests
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/eduardamirelly/tasker/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStats(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	t.Run("empty database", func(t *testing.T) {
+		clearTestTasks(t)
+		assert.Equal(t, "0 tasks, 0% done, avg n/a, 0 overdue\n", runCommand(t, "stats", "--summary-only"))
+	})
+
+	clearTestTasks(t)
+	created := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	// Four done tasks taking 1d, 2d, 2d 12h and 3d 12h: 2d 6h on average
+	for i, took := range []time.Duration{24 * time.Hour, 48 * time.Hour, 60 * time.Hour, 84 * time.Hour} {
+		completed := created.Add(took)
+		insertTestTaskWithSpecificTime(t, fmt.Sprintf("Done %d", i), "", true, created, &completed)
+	}
+	// Six pending tasks, three of them overdue
+	past := time.Now().Add(-48 * time.Hour)
+	for i := 0; i < 6; i++ {
+		id := insertTestTask(t, fmt.Sprintf("Pending %d", i), "", false)
+		if i < 3 {
+			_, err := database.GetDB().Exec(`UPDATE tasks SET due_date = ? WHERE id = ?`, past, id)
+			require.NoError(t, err)
+		}
+	}
+
+	t.Run("summary only", func(t *testing.T) {
+		assert.Equal(t, "10 tasks, 40% done, avg 2d, 3 overdue\n", runCommand(t, "stats", "--summary-only"))
+	})
+
+	t.Run("full report", func(t *testing.T) {
+		output := runCommand(t, "stats")
+		assert.Contains(t, output, "Total tasks:     10\n")
+		assert.Contains(t, output, "Done:            4 (40%)\n")
+		assert.Contains(t, output, "Pending:         6\n")
+		assert.Contains(t, output, "Overdue:         3\n")
+		assert.Contains(t, output, "Avg completion:  2d 6h\n")
+	})
+}
+
+func TestStatsPeriod(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+	complete := func(created time.Time, took time.Duration) {
+		completed := created.Add(took)
+		insertTestTaskWithSpecificTime(t, "Done", "", true, created, &completed)
+	}
+	// February: one task taking 4 days
+	complete(time.Date(2024, 2, 10, 9, 0, 0, 0, time.Local), 96*time.Hour)
+	// March: two tasks taking 1 and 3 days, one completed on the last day
+	complete(time.Date(2024, 3, 1, 9, 0, 0, 0, time.Local), 24*time.Hour)
+	complete(time.Date(2024, 3, 28, 9, 0, 0, 0, time.Local), 72*time.Hour)
+	// April: one task taking 10 days
+	complete(time.Date(2024, 4, 1, 9, 0, 0, 0, time.Local), 240*time.Hour)
+	// Pending tasks never count within a period
+	insertTestTask(t, "Pending", "", false)
+
+	t.Run("completions outside the window are excluded", func(t *testing.T) {
+		output := runCommand(t, "stats", "--from", "2024-03-01", "--to", "2024-03-31")
+		assert.Equal(t, "Period:          2024-03-01 00:00 to 2024-04-01 00:00\n"+
+			"Completed:       2\n"+
+			"Avg completion:  2d\n", output)
+	})
+
+	t.Run("summary only", func(t *testing.T) {
+		output := runCommand(t, "stats", "--from", "2024-03-01", "--to", "2024-03-31", "--summary-only")
+		assert.Equal(t, "2 done, avg 2d (2024-03-01 00:00 to 2024-04-01 00:00)\n", output)
+	})
+
+	t.Run("open ended", func(t *testing.T) {
+		output := runCommand(t, "stats", "--from", "2024-03-01", "--summary-only")
+		assert.Equal(t, "3 done, avg 4d (since 2024-03-01 00:00)\n", output)
+
+		output = runCommand(t, "stats", "--to", "2024-02-29", "--summary-only")
+		assert.Equal(t, "1 done, avg 4d (before 2024-03-01 00:00)\n", output)
+	})
+
+	t.Run("relative dates", func(t *testing.T) {
+		output := runCommand(t, "stats", "--from", "30d", "--summary-only")
+		assert.Contains(t, output, "0 done, avg n/a (since ")
+
+		recent := time.Now().Add(-72 * time.Hour)
+		complete(recent, 24*time.Hour)
+		output = runCommand(t, "stats", "--from", "1w", "--to", "today", "--summary-only")
+		assert.Contains(t, output, "1 done, avg 1d (")
+	})
+
+	t.Run("without a window every task counts", func(t *testing.T) {
+		output := runCommand(t, "stats")
+		assert.Contains(t, output, "Total tasks:     6\n")
+		assert.Contains(t, output, "Done:            5 (83%)\n")
+	})
+
+	t.Run("invalid windows", func(t *testing.T) {
+		assert.Contains(t, runCommand(t, "stats", "--from", "last quarter"), `Error computing stats: invalid --from: invalid time "last quarter"`)
+		assert.Contains(t, runCommand(t, "stats", "--from", "2024-04-01", "--to", "2024-03-01"), "Error computing stats: --from must be before --to")
+	})
+}
@@ -0,0 +1,76 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/eduardamirelly/tasker/database"
+	"github.com/eduardamirelly/tasker/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTags(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	t.Run("lists distinct tags by frequency", func(t *testing.T) {
+		clearTestTasks(t)
+		runCommand(t, "add", "Fix login bug", "--tag", "work", "--tag", "urgent")
+		runCommand(t, "add", "Write report", "--tag", "Work,writing")
+		runCommand(t, "add", "Plan sprint", "--tag", "work", "--tag", "#urgent")
+		runCommand(t, "add", "Buy milk", "--tag", "home")
+		runCommand(t, "add", "Untagged task")
+
+		output := runCommand(t, "tags")
+		assert.Equal(t, fmt.Sprintf("%-20s 3\n%-20s 2\n%-20s 1\n%-20s 1\n", "work", "urgent", "home", "writing"), output)
+	})
+
+	t.Run("show prints the tags", func(t *testing.T) {
+		clearTestTasks(t)
+		runCommand(t, "add", "Fix login bug", "--tag", "work,urgent,work")
+
+		var id int
+		require.NoError(t, database.GetDB().QueryRow(`SELECT id FROM tasks`).Scan(&id))
+		output := runCommand(t, "show", fmt.Sprint(id))
+		assert.Contains(t, output, "Tags: urgent, work\n")
+	})
+
+	t.Run("tags of removed tasks are not counted", func(t *testing.T) {
+		clearTestTasks(t)
+		output := runCommand(t, "tags")
+		assert.Equal(t, "No tags in use\n", output)
+	})
+
+	t.Run("tags load for more tasks than one query holds", func(t *testing.T) {
+		clearTestTasks(t)
+		_, err := database.GetDB().Exec(`DELETE FROM task_tags`)
+		require.NoError(t, err)
+		tx, err := database.GetDB().Begin()
+		require.NoError(t, err)
+		const count = 1234
+		for i := 1; i <= count; i++ {
+			_, err := tx.Exec(`INSERT INTO tasks (id, title, description) VALUES (?, ?, '')`, i, fmt.Sprintf("Task %d", i))
+			require.NoError(t, err)
+			_, err = tx.Exec(`INSERT INTO task_tags (task_id, tag) VALUES (?, ?)`, i, fmt.Sprintf("tag%d", i%7))
+			require.NoError(t, err)
+		}
+		require.NoError(t, tx.Commit())
+
+		var tasks []models.Task
+		require.NoError(t, json.Unmarshal([]byte(runCommand(t, "list", "--all", "--json")), &tasks))
+		require.Len(t, tasks, count)
+		for _, task := range tasks {
+			assert.Equal(t, []string{fmt.Sprintf("tag%d", task.ID%7)}, task.Tags, "task %d", task.ID)
+		}
+	})
+
+	t.Run("invalid tag", func(t *testing.T) {
+		clearTestTasks(t)
+		output := runCommand(t, "add", "Bad tag", "--tag", "two words")
+		assert.Contains(t, output, "tags cannot contain spaces")
+		assert.Equal(t, 0, getTaskCount(t))
+	})
+}
@@ -2,10 +2,16 @@ package tests
 
 import (
 	"database/sql"
+	"io"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"testing"
+	"time"
 
+	"github.com/eduardamirelly/tasker/cmd"
+	"github.com/eduardamirelly/tasker/config"
 	"github.com/eduardamirelly/tasker/database"
 	_ "github.com/mattn/go-sqlite3"
 )
@@ -22,9 +28,11 @@ func setupTestDB(t *testing.T) func() {
 		t.Fatalf("Failed to open test database: %v", err)
 	}
 
+	// Never pick up the developer's own config file
+	t.Setenv(config.PathEnv, filepath.Join(tempDir, "config.json"))
+
 	// Store original DB and replace with test DB
-	originalDB := database.DB
-	database.DB = db
+	originalDB := database.SetDB(db)
 
 	// Create tables in test database
 	err = createTestTables()
@@ -34,8 +42,7 @@ func setupTestDB(t *testing.T) func() {
 
 	// Return cleanup function
 	return func() {
-		database.DB.Close()
-		database.DB = originalDB
+		database.SetDB(originalDB).Close()
 		os.Remove(testDBPath)
 	}
 }
@@ -52,14 +59,18 @@ func createTestTables() error {
 		completed_at DATETIME
 	);`
 
-	_, err := database.DB.Exec(query)
-	return err
+	if _, err := database.GetDB().Exec(query); err != nil {
+		return err
+	}
+
+	// Bring the schema up to date with the columns added since
+	return database.Migrate()
 }
 
 // insertTestTask is a helper function to insert a test task
 func insertTestTask(t *testing.T, title, description string, done bool) int {
 	query := `INSERT INTO tasks (title, description, done) VALUES (?, ?, ?)`
-	result, err := database.DB.Exec(query, title, description, done)
+	result, err := database.GetDB().Exec(query, title, description, done)
 	if err != nil {
 		t.Fatalf("Failed to insert test task: %v", err)
 	}
@@ -75,7 +86,7 @@ func insertTestTask(t *testing.T, title, description string, done bool) int {
 // getTaskCount returns the number of tasks in the database
 func getTaskCount(t *testing.T) int {
 	var count int
-	err := database.DB.QueryRow("SELECT COUNT(*) FROM tasks").Scan(&count)
+	err := database.GetDB().QueryRow("SELECT COUNT(*) FROM tasks").Scan(&count)
 	if err != nil {
 		t.Fatalf("Failed to get task count: %v", err)
 	}
@@ -86,7 +97,7 @@ func getTaskCount(t *testing.T) int {
 func getTaskByID(t *testing.T, id int) *testTask {
 	query := `SELECT id, title, description, done FROM tasks WHERE id = ?`
 	var task testTask
-	err := database.DB.QueryRow(query, id).Scan(&task.ID, &task.Title, &task.Description, &task.Done)
+	err := database.GetDB().QueryRow(query, id).Scan(&task.ID, &task.Title, &task.Description, &task.Done)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil
@@ -106,8 +117,94 @@ type testTask struct {
 
 // clearTestTasks removes all tasks from the test database
 func clearTestTasks(t *testing.T) {
-	_, err := database.DB.Exec("DELETE FROM tasks")
+	_, err := database.GetDB().Exec("DELETE FROM tasks")
 	if err != nil {
 		t.Fatalf("Failed to clear test tasks: %v", err)
 	}
 }
+
+// runCommand executes a tasker command against the test database and returns
+// everything it printed to stdout
+func runCommand(t *testing.T, args ...string) string {
+	t.Helper()
+
+	out, err := runCommandErr(t, args...)
+	if err != nil {
+		t.Fatalf("Failed to run command %v: %v", args, err)
+	}
+	return out
+}
+
+// runCommandErr is like runCommand but returns the command error instead of
+// failing the test, for commands expected to exit with an error
+func runCommandErr(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+
+	originalStdout := os.Stdout
+	os.Stdout = w
+
+	output := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		output <- string(data)
+	}()
+
+	runErr := cmd.ExecuteArgs(args...)
+
+	w.Close()
+	os.Stdout = originalStdout
+	out := <-output
+	r.Close()
+
+	return out, runErr
+}
+
+// listedTaskIDPattern matches the "<status> <id> - <title>" line printed per task by list
+var listedTaskIDPattern = regexp.MustCompile(`(?m)^\S+ (\d+) - `)
+
+// listedTaskIDs extracts the task IDs, in display order, from list output
+func listedTaskIDs(t *testing.T, output string) []int {
+	t.Helper()
+
+	var ids []int
+	for _, match := range listedTaskIDPattern.FindAllStringSubmatch(output, -1) {
+		id, err := strconv.Atoi(match[1])
+		if err != nil {
+			t.Fatalf("Failed to parse listed task ID %q: %v", match[1], err)
+		}
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// insertTestTaskWithDueDate inserts a test task with the given due date
+func insertTestTaskWithDueDate(t *testing.T, title string, done bool, dueDate *time.Time) int {
+	query := `INSERT INTO tasks (title, description, done, due_date) VALUES (?, '', ?, ?)`
+	result, err := database.GetDB().Exec(query, title, done, dueDate)
+	if err != nil {
+		t.Fatalf("Failed to insert test task: %v", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		t.Fatalf("Failed to get last insert ID: %v", err)
+	}
+
+	return int(id)
+}
+
+// writeTestConfig writes content as the config file used by commands in this test
+func writeTestConfig(t *testing.T, content string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("Failed to write test config: %v", err)
+	}
+	t.Setenv(config.PathEnv, path)
+}
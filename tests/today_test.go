@@ -0,0 +1,92 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTodayCommand(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	now := time.Now()
+	startOfToday := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	yesterday := startOfToday.Add(-time.Hour)
+
+	t.Run("splits tasks into created and completed sections", func(t *testing.T) {
+		clearTestTasks(t)
+		insertTestTaskWithSpecificTime(t, "Old and still pending", "", false, yesterday, nil)
+		insertTestTaskWithSpecificTime(t, "Old finished yesterday", "", true, yesterday, &yesterday)
+		insertTestTaskWithSpecificTime(t, "Old finished today", "", true, yesterday, &now)
+		insertTestTaskWithSpecificTime(t, "New pending", "", false, now, nil)
+		insertTestTaskWithSpecificTime(t, "New finished", "", true, now, &now)
+
+		output := runCommand(t, "today")
+
+		parts := strings.SplitN(output, "Completed today", 2)
+		require.Len(t, parts, 2)
+		createdSection, completedSection := parts[0], parts[1]
+
+		assert.Contains(t, createdSection, "Created today (2):")
+		assert.Contains(t, createdSection, "New pending")
+		assert.Contains(t, createdSection, "New finished")
+		assert.NotContains(t, createdSection, "Old")
+
+		assert.Contains(t, completedSection, "(2):")
+		assert.Contains(t, completedSection, "Old finished today")
+		assert.Contains(t, completedSection, "New finished")
+		assert.NotContains(t, completedSection, "Old finished yesterday")
+		assert.NotContains(t, completedSection, "pending")
+	})
+
+	t.Run("empty sections", func(t *testing.T) {
+		clearTestTasks(t)
+		insertTestTaskWithSpecificTime(t, "Old task", "", false, yesterday, nil)
+
+		output := runCommand(t, "today")
+
+		assert.Contains(t, output, "Created today (0):")
+		assert.Contains(t, output, "Completed today (0):")
+		assert.Contains(t, output, "None")
+	})
+}
+
+func TestTodaySameCompletionTime(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+	now := time.Now()
+	earlier := now.Add(-time.Minute)
+	if earlier.Day() != now.Day() {
+		t.Skip("too close to midnight for two completions on the same day")
+	}
+	// Added in reverse order of completion, so only the completion order can
+	// explain the output
+	batchB := insertTestTaskWithSpecificTime(t, "Batch B", "", false, now.Add(-2*time.Hour), nil)
+	batchA := insertTestTaskWithSpecificTime(t, "Batch A", "", false, now.Add(-time.Hour), nil)
+	batchC := insertTestTaskWithSpecificTime(t, "Batch C", "", false, now.Add(-time.Hour), nil)
+	first := insertTestTaskWithSpecificTime(t, "Finished first", "", true, now.Add(-3*time.Hour), &earlier)
+
+	// Completing by filter stamps every task with the same time
+	runCommand(t, "done", "--title-prefix", "Batch", "--yes")
+
+	t.Run("lists completions in order, ties by id", func(t *testing.T) {
+		output := runCommand(t, "today")
+		completed := strings.SplitN(output, "Completed today", 2)[1]
+		assert.Contains(t, completed, "(4):")
+		assert.Equal(t, []int{first, batchB, batchA, batchC}, listedTaskIDs(t, completed))
+	})
+
+	t.Run("counts every completion of the day", func(t *testing.T) {
+		runCommand(t, "goal", "set", "5", "--per", "day")
+		assert.Contains(t, runCommand(t, "goal"), "Today: 4/5 tasks (80%)")
+		assert.Contains(t, runCommand(t, "stats", "--from", "today"), "Completed:       4")
+	})
+}
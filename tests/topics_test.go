@@ -0,0 +1,48 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHelpTopics(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	t.Run("lists the known topics", func(t *testing.T) {
+		output := runCommand(t, "help-topics")
+
+		for _, topic := range []string{"filters", "formats", "dates", "config"} {
+			assert.Contains(t, output, "  "+topic)
+		}
+	})
+
+	t.Run("filters guide lists every filter flag", func(t *testing.T) {
+		output := runCommand(t, "help-topics", "filters")
+
+		for _, flag := range []string{"--title-contains string", "--title-prefix string", "--overdue ", "--overdue-days uint", "--due-today", "--due-this-week"} {
+			assert.Contains(t, output, flag)
+		}
+		assert.NotContains(t, output, "--jsonl")
+	})
+
+	t.Run("formats guide", func(t *testing.T) {
+		output := runCommand(t, "help-topics", "formats")
+		assert.Contains(t, output, "ID, Title, Description, Done, Created At, Completed At")
+		assert.Contains(t, output, "--format json")
+		assert.Contains(t, output, "--format sql")
+	})
+
+	t.Run("unknown topic", func(t *testing.T) {
+		output := runCommand(t, "help-topics", "recipes")
+		assert.Contains(t, output, `Unknown help topic "recipes". Available topics: filters, formats, dates, config`)
+	})
+
+	t.Run("hidden from the command list", func(t *testing.T) {
+		output := runCommand(t, "--help")
+		assert.Contains(t, output, "Available Commands")
+		assert.NotContains(t, output, "help-topics")
+	})
+}
@@ -0,0 +1,67 @@
+package tests
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eduardamirelly/tasker/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUndone(t *testing.T) {
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	completedAt := time.Now().Add(-time.Hour)
+
+	t.Run("clears done and the completion time", func(t *testing.T) {
+		clearTestTasks(t)
+		id := insertTestTaskWithSpecificTime(t, "Wrong task", "", true, time.Now().Add(-2*time.Hour), &completedAt)
+
+		output := runCommand(t, "undone", fmt.Sprint(id))
+		assert.Contains(t, output, "Task reopened: Wrong task")
+		assert.Equal(t, 1, strings.Count(output, "Task reopened"))
+
+		var done bool
+		var completed sql.NullTime
+		err := database.GetDB().QueryRow(`SELECT done, completed_at FROM tasks WHERE id = ?`, id).Scan(&done, &completed)
+		require.NoError(t, err)
+		assert.False(t, done)
+		assert.False(t, completed.Valid)
+	})
+
+	t.Run("reopen is an alias that also drops the note", func(t *testing.T) {
+		clearTestTasks(t)
+		id := insertTestTask(t, "Send report", "", false)
+		runCommand(t, "done", fmt.Sprint(id), "--note", "Sent by mistake")
+
+		runCommand(t, "reopen", fmt.Sprint(id))
+
+		var done bool
+		var completed sql.NullTime
+		var note sql.NullString
+		err := database.GetDB().QueryRow(`SELECT done, completed_at, completion_note FROM tasks WHERE id = ?`, id).Scan(&done, &completed, &note)
+		require.NoError(t, err)
+		assert.False(t, done)
+		assert.False(t, completed.Valid)
+		assert.False(t, note.Valid)
+	})
+
+	t.Run("task that is not done is left alone", func(t *testing.T) {
+		clearTestTasks(t)
+		id := insertTestTask(t, "Still pending", "", false)
+
+		output := runCommand(t, "undone", fmt.Sprint(id))
+		assert.Contains(t, output, fmt.Sprintf("Task %d is not done yet, nothing to reopen", id))
+		assert.False(t, getTaskByID(t, id).Done)
+	})
+
+	t.Run("unknown task", func(t *testing.T) {
+		output := runCommand(t, "undone", "99999")
+		assert.Contains(t, output, "❌ Task not found: 99999")
+	})
+}
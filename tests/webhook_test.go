@@ -0,0 +1,207 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/eduardamirelly/tasker/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// webhookRecorder is a test server recording the events posted to it
+type webhookRecorder struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	events []webhook.Event
+}
+
+func newWebhookRecorder(t *testing.T, status int) *webhookRecorder {
+	t.Helper()
+
+	recorder := &webhookRecorder{}
+	recorder.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event webhook.Event
+		if assert.Equal(t, http.MethodPost, r.Method) && assert.NoError(t, json.NewDecoder(r.Body).Decode(&event)) {
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			recorder.mu.Lock()
+			recorder.events = append(recorder.events, event)
+			recorder.mu.Unlock()
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(recorder.Close)
+	return recorder
+}
+
+// take returns the events received so far and forgets them
+func (r *webhookRecorder) take() []webhook.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	events := r.events
+	r.events = nil
+	return events
+}
+
+func TestWebhook(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	clearTestTasks(t)
+	recorder := newWebhookRecorder(t, http.StatusNoContent)
+	t.Setenv("TASKER_WEBHOOK", recorder.URL)
+
+	var id int
+	t.Run("add", func(t *testing.T) {
+		output := runCommand(t, "add", "Write report", "--tag", "work", "--priority", "high")
+		assert.NotContains(t, output, "Warning")
+
+		// Deliveries finish before the command returns
+		events := recorder.take()
+		require.Len(t, events, 1)
+		assert.Equal(t, webhook.TaskAdded, events[0].Type)
+		assert.Equal(t, "Write report", events[0].Task.Title)
+		assert.Equal(t, "high", events[0].Task.Priority)
+		assert.Equal(t, []string{"work"}, events[0].Task.Tags)
+		assert.NotEmpty(t, events[0].Task.UUID)
+		assert.False(t, events[0].Time.IsZero())
+		id = events[0].Task.ID
+	})
+
+	t.Run("edit lists the changed fields", func(t *testing.T) {
+		runCommand(t, "edit", fmt.Sprint(id), "--title", "Write the report")
+
+		events := recorder.take()
+		require.Len(t, events, 1)
+		assert.Equal(t, webhook.TaskUpdated, events[0].Type)
+		assert.Equal(t, []webhook.Change{{Field: "title", Before: "Write report", After: "Write the report"}}, events[0].Changes)
+	})
+
+	t.Run("done", func(t *testing.T) {
+		runCommand(t, "done", fmt.Sprint(id))
+
+		events := recorder.take()
+		require.Len(t, events, 1)
+		assert.Equal(t, webhook.TaskCompleted, events[0].Type)
+		assert.Equal(t, id, events[0].Task.ID)
+		assert.True(t, events[0].Task.Done)
+		assert.NotNil(t, events[0].Task.CompletedAt)
+	})
+
+	t.Run("done with filters sends one event per task", func(t *testing.T) {
+		runCommand(t, "add", "Fix bug one")
+		runCommand(t, "add", "Fix bug two")
+		recorder.take()
+
+		runCommand(t, "done", "--title-prefix", "Fix bug", "--yes")
+		events := recorder.take()
+		require.Len(t, events, 2)
+		for _, event := range events {
+			assert.Equal(t, webhook.TaskCompleted, event.Type)
+			assert.True(t, event.Task.Done)
+		}
+	})
+
+	t.Run("import sends added and updated events", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "tasks.csv")
+		content := "ID,Title,Description,Done,Created At,Completed At\n" +
+			fmt.Sprintf("%d,Write the final report,,false,2024-05-01 10:00:00,\n", id) +
+			"900,Imported task,,false,2024-05-01 10:00:00,\n"
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+		runCommand(t, "import", path, "--on-conflict", "overwrite")
+		events := recorder.take()
+		require.Len(t, events, 2)
+		byType := map[string]webhook.Event{}
+		for _, event := range events {
+			byType[event.Type] = event
+		}
+		assert.Equal(t, 900, byType[webhook.TaskAdded].Task.ID)
+		assert.Equal(t, "Imported task", byType[webhook.TaskAdded].Task.Title)
+		assert.Equal(t, id, byType[webhook.TaskUpdated].Task.ID)
+		assert.Equal(t, "Write the final report", byType[webhook.TaskUpdated].Task.Title)
+		assert.False(t, byType[webhook.TaskUpdated].Task.Done)
+	})
+
+	t.Run("read commands send nothing", func(t *testing.T) {
+		runCommand(t, "list")
+		runCommand(t, "show", fmt.Sprint(id))
+		assert.Empty(t, recorder.take())
+	})
+
+	t.Run("no webhook configured", func(t *testing.T) {
+		t.Setenv("TASKER_WEBHOOK", "")
+		runCommand(t, "add", "Quiet task")
+		assert.Empty(t, recorder.take())
+	})
+
+	t.Run("URL from the config file", func(t *testing.T) {
+		t.Setenv("TASKER_WEBHOOK", "")
+		writeTestConfig(t, fmt.Sprintf(`{"webhook": %q}`, recorder.URL))
+		runCommand(t, "add", "Configured task")
+		assert.Len(t, recorder.take(), 1)
+	})
+}
+
+func TestWebhookFailures(t *testing.T) {
+	// Setup test database
+	cleanup := setupTestDB(t)
+	defer cleanup()
+
+	t.Run("error response is a warning", func(t *testing.T) {
+		clearTestTasks(t)
+		recorder := newWebhookRecorder(t, http.StatusInternalServerError)
+		t.Setenv("TASKER_WEBHOOK", recorder.URL)
+
+		output := runCommand(t, "add", "Still saved")
+		assert.Contains(t, output, "✓ Task added: Still saved")
+		assert.Contains(t, output, "⚠️  Warning: webhook delivery failed: task.added for task")
+		assert.Contains(t, output, "500 Internal Server Error")
+		assert.Equal(t, 1, getTaskCount(t))
+	})
+
+	t.Run("unreachable webhook is a warning", func(t *testing.T) {
+		clearTestTasks(t)
+		recorder := newWebhookRecorder(t, http.StatusOK)
+		recorder.Close()
+		t.Setenv("TASKER_WEBHOOK", recorder.URL)
+
+		output := runCommand(t, "add", "Saved anyway")
+		assert.Contains(t, output, "✓ Task added: Saved anyway")
+		assert.Contains(t, output, "webhook delivery failed")
+		assert.Equal(t, 1, getTaskCount(t))
+	})
+
+	t.Run("invalid URL is a warning", func(t *testing.T) {
+		clearTestTasks(t)
+		t.Setenv("TASKER_WEBHOOK", "ftp://example.com")
+
+		output := runCommand(t, "add", "Added anyway")
+		assert.Contains(t, output, "⚠️  Warning: webhook disabled: invalid webhook URL")
+		assert.Contains(t, output, "✓ Task added: Added anyway")
+		assert.Equal(t, 1, getTaskCount(t))
+	})
+
+	t.Run("read commands work with an invalid URL", func(t *testing.T) {
+		clearTestTasks(t)
+		id := insertTestTask(t, "Listed task", "", false)
+		t.Setenv("TASKER_WEBHOOK", "not-a-url")
+
+		output, err := runCommandErr(t, "list")
+		require.NoError(t, err)
+		assert.Contains(t, output, "webhook disabled")
+		assert.Contains(t, output, "Listed task")
+
+		output, err = runCommandErr(t, "show", fmt.Sprint(id))
+		require.NoError(t, err)
+		assert.Contains(t, output, "Listed task")
+	})
+}
@@ -0,0 +1,105 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/eduardamirelly/tasker/models"
+)
+
+// Event types sent to the webhook
+const (
+	TaskAdded     = "task.added"
+	TaskCompleted = "task.completed"
+	TaskReopened  = "task.reopened"
+	TaskUpdated   = "task.updated"
+)
+
+// Timeout bounds each delivery, so an unresponsive endpoint can only delay a
+// command briefly
+const Timeout = 3 * time.Second
+
+// Change is one field of a task changed by an update
+type Change struct {
+	Field  string `json:"field"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// Event is the JSON payload posted for each change to a task
+type Event struct {
+	Type string      `json:"event"`
+	Time time.Time   `json:"time"`
+	Task models.Task `json:"task"`
+	// Changes lists the changed fields of task.updated events
+	Changes []Change `json:"changes,omitempty"`
+}
+
+// Client posts events to a webhook URL in the background. Delivery is best
+// effort: failures are collected for Wait to report, never retried.
+type Client struct {
+	url  string
+	http *http.Client
+
+	wg   sync.WaitGroup
+	mu   sync.Mutex
+	errs []error
+}
+
+// New returns a client posting to rawURL, which must be an http(s) URL
+func New(rawURL string) (*Client, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid webhook URL %q, expected an http or https URL", rawURL)
+	}
+	return &Client{url: rawURL, http: &http.Client{Timeout: Timeout}}, nil
+}
+
+// Send posts event without waiting for the response
+func (c *Client) Send(event Event) {
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		if err := c.post(event); err != nil {
+			c.mu.Lock()
+			c.errs = append(c.errs, fmt.Errorf("%s for task %d: %w", event.Type, event.Task.ID, err))
+			c.mu.Unlock()
+		}
+	}()
+}
+
+// Wait blocks until every event sent so far was delivered or failed, and
+// returns the failures
+func (c *Client) Wait() []error {
+	c.wg.Wait()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	errs := c.errs
+	c.errs = nil
+	return errs
+}
+
+// post delivers one event, treating any non-2xx response as a failure
+func (c *Client) post(event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	response, err := c.http.Post(c.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with %s", response.Status)
+	}
+	return nil
+}